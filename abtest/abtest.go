@@ -0,0 +1,274 @@
+// Package abtest compares two Players by playing duplicate deals and testing
+// the difference in chips won with a sequential probability ratio test, in
+// the manner of chess engine testing.
+//
+// Each pair of hands plays one deal twice at a table of otherwise identical
+// baseline Players: once with the candidate in one seat, and once with a
+// baseline Player in that seat. The seat rotates from pair to pair. The
+// difference in the chips won from that seat measures the candidate against
+// the baseline, with much of the luck of the deal cancelled out.
+//
+// The test weighs the hypothesis H0 that the mean difference per pair is Mu0
+// against H1 that it is Mu1, and stops as soon as the evidence favors one with
+// the given error rates: accepting H1 is significance, and accepting H0 is
+// futility.
+package abtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Status is the state of a sequential test.
+type Status int
+
+const (
+	// Continue means that the test has not reached a decision.
+	Continue Status = iota
+
+	// AcceptH0 means that the test accepted H0: the candidate is no better
+	// than Mu0.
+	AcceptH0
+
+	// AcceptH1 means that the test accepted H1: the candidate is better by
+	// Mu1.
+	AcceptH1
+)
+
+func (s Status) String() string {
+	switch s {
+	case Continue:
+		return "inconclusive"
+	case AcceptH0:
+		return "H0 accepted"
+	case AcceptH1:
+		return "H1 accepted"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// An SPRT is a sequential probability ratio test of the mean of a series of
+// samples, approximating their distribution as normal with the variance of
+// the samples so far.
+type SPRT struct {
+	// Mu0 and Mu1 are the means under H0 and H1.
+	Mu0, Mu1 float64
+
+	// Alpha and Beta are the probabilities of accepting H1 when H0 holds,
+	// and H0 when H1 holds.
+	Alpha, Beta float64
+
+	n          int
+	sum, sumsq float64
+}
+
+// Add adds a sample.
+func (t *SPRT) Add(x float64) {
+	t.n++
+	t.sum += x
+	t.sumsq += x * x
+}
+
+// N returns the number of samples.
+func (t *SPRT) N() int { return t.n }
+
+// Mean returns the mean of the samples, and the half-width of its 95%
+// confidence interval.
+func (t *SPRT) Mean() (mean, err float64) {
+	if t.n == 0 {
+		return 0, math.Inf(1)
+	}
+	mean = t.sum / float64(t.n)
+	return mean, 1.96 * math.Sqrt(t.variance()/float64(t.n))
+}
+
+// variance returns the variance of the samples.
+func (t *SPRT) variance() float64 {
+	if t.n < 2 {
+		return 0
+	}
+	mean := t.sum / float64(t.n)
+	return (t.sumsq - float64(t.n)*mean*mean) / float64(t.n-1)
+}
+
+// LLR returns the log-likelihood ratio of H1 to H0, or 0 until there are
+// minSamples samples.
+func (t *SPRT) LLR() float64 {
+	if t.n < minSamples {
+		return 0
+	}
+	// Samples that are all the same determine the mean exactly.
+	v := math.Max(t.variance(), minVariance)
+	return (t.Mu1 - t.Mu0) * (2*t.sum - float64(t.n)*(t.Mu0+t.Mu1)) / (2 * v)
+}
+
+// minVariance is the least variance that LLR assumes.
+const minVariance = 1e-9
+
+// minSamples is the number of samples below which the variance of the samples
+// is too uncertain for LLR to rely on, as when a Player makes few decisions
+// that the other does not and most pairs are drawn.
+const minSamples = 30
+
+// Bounds returns the values of the LLR at or below which the test accepts H0,
+// and at or above which it accepts H1.
+func (t *SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// Status returns the state of the test.
+func (t *SPRT) Status() Status {
+	lower, upper := t.Bounds()
+	switch llr := t.LLR(); {
+	case llr >= upper:
+		return AcceptH1
+	case llr <= lower:
+		return AcceptH0
+	}
+	return Continue
+}
+
+// Elo returns the difference in Elo rating implied by the scores of a number
+// of wins, draws and losses, and the half-width of its 95% confidence
+// interval.
+func Elo(wins, draws, losses int) (elo, err float64) {
+	n := float64(wins + draws + losses)
+	if n == 0 {
+		return 0, math.Inf(1)
+	}
+	s := (float64(wins) + float64(draws)/2) / n
+	v := (float64(wins)*(1-s)*(1-s) + float64(draws)*(0.5-s)*(0.5-s) + float64(losses)*s*s) / n
+	d := 1.96 * math.Sqrt(v/n)
+	return rating(s), (rating(s+d) - rating(s-d)) / 2
+}
+
+// rating returns the difference in Elo rating implied by an expected score.
+func rating(s float64) float64 {
+	const eps = 1e-6
+	s = math.Max(eps, math.Min(1-eps, s))
+	return 400 * math.Log10(s/(1-s))
+}
+
+// A Config configures a comparison.
+//
+// Each hand of a pair is the first hand of a new Game, so no stake or Kitty
+// carries over from earlier hands, and the comparison measures play only in
+// the first hand of a game.
+type Config struct {
+	Rules   game.Rules
+	Players int
+
+	// Candidate and Baseline return new Players seeded with the given
+	// value.
+	Candidate, Baseline func(seed int64) game.Player
+
+	// Test holds the hypotheses and error rates of the test. A test with
+	// zero error rates never reaches a decision, so that Run plays MaxPairs
+	// pairs.
+	Test SPRT
+
+	// MaxPairs is the number of pairs after which the comparison stops if
+	// the test has not reached a decision.
+	MaxPairs int
+
+	// Seed seeds the deals and Players.
+	Seed int64
+
+	// Every is the number of pairs between calls to Progress, if it is
+	// positive and Progress is not nil.
+	Every    int
+	Progress func(Report)
+}
+
+// A Report describes the state of a comparison.
+type Report struct {
+	Pairs  int
+	Status Status
+
+	// LLR is the log-likelihood ratio of the test, and Lower and Upper its
+	// bounds.
+	LLR, Lower, Upper float64
+
+	// Mean is the mean difference in chips per pair, and MeanErr the
+	// half-width of its 95% confidence interval.
+	Mean, MeanErr float64
+
+	// Wins, Draws and Losses count the pairs in which the candidate won
+	// more, as many, and fewer chips than the baseline.
+	Wins, Draws, Losses int
+
+	// Elo is the difference in Elo rating implied by the pairs' outcomes,
+	// and EloErr the half-width of its 95% confidence interval.
+	Elo, EloErr float64
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%v pairs: %v, LLR %.2f [%.2f, %.2f], chips %+.3f ± %.3f, W/D/L %v/%v/%v, Elo %+.1f ± %.1f",
+		r.Pairs, r.Status, r.LLR, r.Lower, r.Upper, r.Mean, r.MeanErr, r.Wins, r.Draws, r.Losses, r.Elo, r.EloErr)
+}
+
+// Run plays pairs of hands until the test reaches a decision or MaxPairs pairs
+// have been played, and returns the final Report. Run panics if the number of
+// Players is not supported.
+func Run(cfg Config) Report {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	t := cfg.Test
+	var wins, draws, losses int
+	report := func() Report {
+		r := Report{Pairs: t.N(), Status: t.Status(), LLR: t.LLR(), Wins: wins, Draws: draws, Losses: losses}
+		r.Lower, r.Upper = t.Bounds()
+		r.Mean, r.MeanErr = t.Mean()
+		r.Elo, r.EloErr = Elo(wins, draws, losses)
+		return r
+	}
+	for i := 0; i < cfg.MaxPairs && t.Status() == Continue; i++ {
+		d := deal(cfg.Rules.Deck, cfg.Players, rng)
+		seat := i % cfg.Players
+		seed := rng.Int63()
+		x := float64(play(cfg, d, seat, cfg.Candidate, seed) - play(cfg, d, seat, cfg.Baseline, seed))
+		t.Add(x)
+		switch {
+		case x > 0:
+			wins++
+		case x < 0:
+			losses++
+		default:
+			draws++
+		}
+		if cfg.Progress != nil && cfg.Every > 0 && t.N()%cfg.Every == 0 {
+			cfg.Progress(report())
+		}
+	}
+	return report()
+}
+
+// deal shuffles a copy of a deck and deals it as the engine does, one card at
+// a time to each of n players and then the widow.
+func deal(deck card.Deck, n int, rng *rand.Rand) game.Deal {
+	deck = append(card.Deck(nil), deck...)
+	deck.Shuffle(rng)
+	hands := deck.Deal(n + 1)
+	return game.Deal{Hands: hands[:n], Widow: hands[n]}
+}
+
+// play plays a deal with the Player returned by p in the given seat and
+// baseline Players in the others, and returns the chips won from the seat.
+// The Player in each seat is seeded with seed plus the seat's index.
+func play(cfg Config, d game.Deal, seat int, p func(int64) game.Player, seed int64) int {
+	players := make([]game.Player, cfg.Players)
+	for i := range players {
+		if i == seat {
+			players[i] = p(seed + int64(i))
+		} else {
+			players[i] = cfg.Baseline(seed + int64(i))
+		}
+	}
+	g := game.New(players, cfg.Rules)
+	// The last Player deals, so that each Player's position is its index.
+	g.PlayDeal(d, cfg.Players-1)
+	return g.State().Score[seat]
+}
@@ -0,0 +1,108 @@
+package abtest
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func TestSPRT(t *testing.T) {
+	for name, test := range map[string]struct {
+		mean float64
+		want Status
+	}{
+		"H0":   {0, AcceptH0},
+		"H1":   {0.5, AcceptH1},
+		"less": {-0.5, AcceptH0},
+	} {
+		rng := rand.New(rand.NewSource(1))
+		s := SPRT{Mu0: 0, Mu1: 0.5, Alpha: 0.05, Beta: 0.05}
+		for s.Status() == Continue && s.N() < 100000 {
+			s.Add(test.mean + 2*rng.NormFloat64())
+		}
+		if got := s.Status(); got != test.want {
+			t.Errorf("SPRT(%q): %v after %v samples, expected %v", name, got, s.N(), test.want)
+		}
+	}
+}
+
+func TestElo(t *testing.T) {
+	for name, test := range map[string]struct {
+		w, d, l int
+		elo     float64
+	}{
+		"even":  {10, 0, 10, 0},
+		"draws": {0, 7, 0, 0},
+		"3:1":   {3, 0, 1, 400 * math.Log10(3)},
+		"drawn": {2, 2, 0, 400 * math.Log10(3)},
+	} {
+		if elo, _ := Elo(test.w, test.d, test.l); math.Abs(elo-test.elo) > 1e-9 {
+			t.Errorf("Elo(%q) = %v, expected %v", name, elo, test.elo)
+		}
+	}
+	if _, err := Elo(30, 40, 30); !(err > 0 && err < 100) {
+		t.Errorf("Elo(30, 40, 30): error %v out of range", err)
+	}
+}
+
+func TestDeal(t *testing.T) {
+	deck := card.NewDeck()
+	d := deal(deck, 3, rand.New(rand.NewSource(1)))
+	var all []card.Card
+	for _, h := range d.Hands {
+		if len(h) != 13 {
+			t.Errorf("hand of %v cards, expected 13", len(h))
+		}
+		all = append(all, h...)
+	}
+	all = append(all, d.Widow...)
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	if !reflect.DeepEqual(card.Deck(all), deck) {
+		t.Errorf("deal holds %v, expected the deck", all)
+	}
+}
+
+// fixed is a Player that always leads from the major suit if major is true,
+// and otherwise from the minor suit.
+type fixed struct{ major bool }
+
+func (*fixed) Init(int, int, []card.Card, []int, int) {}
+
+func (*fixed) Note(int, card.Card) {}
+
+func (f *fixed) PlayMajor(game.TableState, card.Color) bool { return f.major }
+
+func TestRun(t *testing.T) {
+	minor := func(int64) game.Player { return &fixed{false} }
+	major := func(int64) game.Player { return &fixed{true} }
+	test := SPRT{Mu0: 0, Mu1: 0.1, Alpha: 0.05, Beta: 0.05}
+	var progress int
+	cfg := Config{
+		Rules:     game.Michigan(),
+		Players:   4,
+		Candidate: minor,
+		Baseline:  minor,
+		Test:      test,
+		MaxPairs:  10000,
+		Seed:      1,
+		Every:     1,
+		Progress:  func(Report) { progress++ },
+	}
+	if r := Run(cfg); r.Status != AcceptH0 || r.Draws != r.Pairs || progress != r.Pairs {
+		t.Errorf("Run(minor, minor) = %v after %v progress reports, expected H0 accepted with only draws", r, progress)
+	}
+
+	cfg.Candidate, cfg.Progress = major, nil
+	r := Run(cfg)
+	if r.Status == Continue || r.Wins+r.Draws+r.Losses != r.Pairs {
+		t.Errorf("Run(major, minor) = %v", r)
+	}
+	if again := Run(cfg); !reflect.DeepEqual(again, r) {
+		t.Errorf("Run(major, minor) = %v, then %v", r, again)
+	}
+}
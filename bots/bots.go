@@ -0,0 +1,344 @@
+// Package bots is a registry of Players by name, so that commands and
+// tournaments can construct Players from a description such as
+//
+//	random:p=0.3,minor,human
+//
+// which lists Players separated by commas. A name may be followed by a colon
+// and an option of the form name=value, and each further option follows a
+// comma.
+//
+// Packages that provide Players register them, usually in an init function:
+//
+//	func init() {
+//		bots.Register(bots.Bot{
+//			Name:    "random",
+//			Help:    "leads from a suit at random",
+//			Options: []bots.Option{{Name: "p", Kind: bots.Float, Default: "0.5", Help: "probability of leading from the major suit"}},
+//			New:     newRandom,
+//		})
+//	}
+//
+// and are available to any program that imports them.
+package bots
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Kind is the type of the value of an Option.
+type Kind int
+
+// The Kinds of Option values.
+const (
+	String Kind = iota
+	Int
+	Float
+	Bool
+)
+
+// An Option is a setting of a Bot.
+type Option struct {
+	Name string
+	Kind Kind
+
+	// Default is the value of the Option if none is given.
+	Default string
+
+	Help string
+}
+
+// A Bot describes a kind of Player.
+type Bot struct {
+	// Name is the name by which the Bot is constructed. It must not be
+	// empty or contain any of ":,=".
+	Name string
+
+	Help    string
+	Options []Option
+
+	// New returns a new Player configured by opts.
+	New func(opts Options) (game.Player, error)
+}
+
+// Options holds the configuration of a Player.
+type Options struct {
+	// Rules are the Rules of the games that the Player will play.
+	Rules game.Rules
+
+	// Seed is a seed for any random number generator of the Player.
+	Seed int64
+
+	bot    *Bot
+	values map[string]string
+}
+
+// value returns the value of an Option, or its default if none was given.
+// value panics if the Bot has no such Option.
+func (o Options) value(name string, kind Kind) string {
+	for _, opt := range o.bot.Options {
+		if opt.Name != name {
+			continue
+		}
+		if opt.Kind != kind {
+			panic(fmt.Sprintf("bots: option %v of %v is not of the requested kind", name, o.bot.Name))
+		}
+		if v, ok := o.values[name]; ok {
+			return v
+		}
+		return opt.Default
+	}
+	panic(fmt.Sprintf("bots: %v has no option %v", o.bot.Name, name))
+}
+
+// String returns the value of a String Option.
+func (o Options) String(name string) string { return o.value(name, String) }
+
+// Int returns the value of an Int Option.
+func (o Options) Int(name string) int {
+	n, _ := strconv.Atoi(o.value(name, Int))
+	return n
+}
+
+// Float returns the value of a Float Option.
+func (o Options) Float(name string) float64 {
+	x, _ := strconv.ParseFloat(o.value(name, Float), 64)
+	return x
+}
+
+// Bool returns the value of a Bool Option.
+func (o Options) Bool(name string) bool {
+	b, _ := strconv.ParseBool(o.value(name, Bool))
+	return b
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]*Bot)
+)
+
+// Register registers a Bot. Register panics if the Bot's name is invalid or is
+// already registered, if it has no New function, or if any Option's default
+// value is not of its Kind.
+func Register(b Bot) {
+	if b.Name == "" || strings.ContainsAny(b.Name, ":,=") {
+		panic(fmt.Sprintf("bots: invalid name %q", b.Name))
+	}
+	if b.New == nil {
+		panic("bots: Register of " + b.Name + " with nil New")
+	}
+	for _, opt := range b.Options {
+		if err := check(opt, opt.Default); err != nil {
+			panic("bots: default of " + b.Name + ": " + err.Error())
+		}
+	}
+	b.Options = append([]Option(nil), b.Options...)
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[b.Name]; ok {
+		panic("bots: Register called twice for " + b.Name)
+	}
+	registry[b.Name] = &b
+}
+
+// check returns an error if a value is not of an Option's Kind.
+func check(opt Option, v string) error {
+	var err error
+	switch opt.Kind {
+	case Int:
+		_, err = strconv.Atoi(v)
+	case Float:
+		_, err = strconv.ParseFloat(v, 64)
+	case Bool:
+		_, err = strconv.ParseBool(v)
+	}
+	if err != nil {
+		return fmt.Errorf("option %v: %q is not %v", opt.Name, v, [...]string{"a string", "an integer", "a number", "a boolean"}[opt.Kind])
+	}
+	return nil
+}
+
+// Lookup returns the Bot registered with a name, and whether there is one.
+func Lookup(name string) (Bot, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	b, ok := registry[name]
+	if !ok {
+		return Bot{}, false
+	}
+	return *b, true
+}
+
+// List returns the registered Bots in order of name.
+func List() []Bot {
+	mu.RLock()
+	defer mu.RUnlock()
+	bots := make([]Bot, 0, len(registry))
+	for _, b := range registry {
+		bots = append(bots, *b)
+	}
+	sort.Slice(bots, func(i, j int) bool { return bots[i].Name < bots[j].Name })
+	return bots
+}
+
+// Help returns a description of the registered Bots and their Options,
+// suitable for the usage message of a command.
+func Help() string {
+	var sb strings.Builder
+	for _, b := range List() {
+		fmt.Fprintf(&sb, "  %v\n", b.Name)
+		if b.Help != "" {
+			fmt.Fprintf(&sb, "    \t%v\n", b.Help)
+		}
+		for _, opt := range b.Options {
+			fmt.Fprintf(&sb, "    %v=%v\n    \t%v\n", opt.Name, opt.Default, opt.Help)
+		}
+	}
+	return sb.String()
+}
+
+// A Spec names a registered Bot and gives values of its Options.
+type Spec struct {
+	Name    string
+	Options map[string]string
+}
+
+// String returns the description of a Spec, with its Options in order of name.
+func (s Spec) String() string {
+	names := make([]string, 0, len(s.Options))
+	for name := range s.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	sb.WriteString(s.Name)
+	for i, name := range names {
+		sep := ","
+		if i == 0 {
+			sep = ":"
+		}
+		fmt.Fprintf(&sb, "%v%v=%v", sep, name, s.Options[name])
+	}
+	return sb.String()
+}
+
+// New returns a new Player as described by a Spec, for games played according
+// to the given Rules, with the given seed.
+func (s Spec) New(rules game.Rules, seed int64) (game.Player, error) {
+	b, err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+	p, err := b.New(Options{Rules: rules, Seed: seed, bot: b, values: s.Options})
+	if err != nil {
+		return nil, fmt.Errorf("bots: %v: %v", s.Name, err)
+	}
+	return p, nil
+}
+
+// validate returns the Bot of a Spec, or an error if it is not registered or
+// its Options are not valid.
+func (s Spec) validate() (*Bot, error) {
+	mu.RLock()
+	b, ok := registry[s.Name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("bots: unknown bot %q", s.Name)
+	}
+next:
+	for name, v := range s.Options {
+		for _, opt := range b.Options {
+			if opt.Name == name {
+				if err := check(opt, v); err != nil {
+					return nil, fmt.Errorf("bots: %v: %v", s.Name, err)
+				}
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("bots: %v has no option %q", s.Name, name)
+	}
+	return b, nil
+}
+
+// Specs is a list of Specs. It implements flag.Value, so that a command may
+// accept a description of its Players as a flag.
+type Specs []Spec
+
+// Parse parses a description of a list of Players and validates it against
+// the registered Bots.
+func Parse(s string) (Specs, error) {
+	var specs Specs
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		name, opt := item, ""
+		colon, eq := strings.IndexByte(item, ':'), strings.IndexByte(item, '=')
+		switch {
+		case eq >= 0 && (colon < 0 || eq < colon):
+			// An option of the preceding Bot.
+			name, opt = "", item
+		case colon >= 0:
+			name, opt = item[:colon], item[colon+1:]
+		}
+		if name != "" {
+			specs = append(specs, Spec{Name: name, Options: make(map[string]string)})
+		}
+		if opt == "" {
+			if name == "" {
+				return nil, fmt.Errorf("bots: empty item in %q", s)
+			}
+			continue
+		}
+		if len(specs) == 0 {
+			return nil, fmt.Errorf("bots: option %q precedes any bot", opt)
+		}
+		eq = strings.IndexByte(opt, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("bots: malformed option %q", opt)
+		}
+		specs[len(specs)-1].Options[opt[:eq]] = opt[eq+1:]
+	}
+	for _, spec := range specs {
+		if _, err := spec.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return specs, nil
+}
+
+// String returns the description of a list of Specs.
+func (s Specs) String() string {
+	items := make([]string, len(s))
+	for i, spec := range s {
+		items[i] = spec.String()
+	}
+	return strings.Join(items, ",")
+}
+
+// Set implements flag.Value by parsing a description of a list of Players.
+func (s *Specs) Set(v string) error {
+	specs, err := Parse(v)
+	if err != nil {
+		return err
+	}
+	*s = specs
+	return nil
+}
+
+// New returns new Players as described by the Specs, for games played
+// according to the given Rules. The ith Player is seeded with seed+i.
+func (s Specs) New(rules game.Rules, seed int64) ([]game.Player, error) {
+	players := make([]game.Player, len(s))
+	for i, spec := range s {
+		p, err := spec.New(rules, seed+int64(i))
+		if err != nil {
+			return nil, err
+		}
+		players[i] = p
+	}
+	return players, nil
+}
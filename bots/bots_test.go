@@ -0,0 +1,181 @@
+package bots
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// settings is a Player that records its Options.
+type settings struct {
+	fixed
+	depth int
+	scale float64
+	name  string
+	fast  bool
+	seed  int64
+}
+
+func init() {
+	Register(Bot{
+		Name: "test",
+		Help: "records its options",
+		Options: []Option{
+			{Name: "depth", Kind: Int, Default: "2", Help: "search depth"},
+			{Name: "scale", Kind: Float, Default: "1.5", Help: "scale"},
+			{Name: "name", Kind: String, Default: "", Help: "name"},
+			{Name: "fast", Kind: Bool, Default: "false", Help: "speed"},
+		},
+		New: func(opts Options) (game.Player, error) {
+			if opts.String("name") == "fail" {
+				return nil, errors.New("failed")
+			}
+			return &settings{
+				depth: opts.Int("depth"),
+				scale: opts.Float("scale"),
+				name:  opts.String("name"),
+				fast:  opts.Bool("fast"),
+				seed:  opts.Seed,
+			}, nil
+		},
+	})
+}
+
+func TestParse(t *testing.T) {
+	for s, want := range map[string]Specs{
+		"minor": {{"minor", map[string]string{}}},
+		"test:depth=5,random,major": {
+			{"test", map[string]string{"depth": "5"}},
+			{"random", map[string]string{}},
+			{"major", map[string]string{}},
+		},
+		"test:depth=5,fast=true, name=a:b ,minor": {
+			{"test", map[string]string{"depth": "5", "fast": "true", "name": "a:b"}},
+			{"minor", map[string]string{}},
+		},
+	} {
+		got, err := Parse(s)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%q) = %v, %v, expected %v", s, got, err, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for s, want := range map[string]string{
+		"":                `bots: empty item in ""`,
+		"minor,,major":    `bots: empty item in "minor,,major"`,
+		"depth=5,test":    `bots: option "depth=5" precedes any bot`,
+		"nobody":          `bots: unknown bot "nobody"`,
+		"test:depth":      `bots: malformed option "depth"`,
+		"test:width=3":    `bots: test has no option "width"`,
+		"test:depth=deep": `bots: test: option depth: "deep" is not an integer`,
+		"minor:p=0.5":     `bots: minor has no option "p"`,
+	} {
+		if _, err := Parse(s); err == nil || err.Error() != want {
+			t.Errorf("Parse(%q): error %v, expected %v", s, err, want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	var specs Specs
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&specs, "players", "the players")
+	if err := fs.Parse([]string{"-players=major,test:scale=0.25,name=x,random"}); err != nil {
+		t.Fatal(err)
+	}
+	if s, want := specs.String(), "major,test:name=x,scale=0.25,random"; s != want {
+		t.Errorf("String() = %q, expected %q", s, want)
+	}
+	players, err := specs.New(game.Michigan(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 3 {
+		t.Fatalf("New returned %v Players, expected 3", len(players))
+	}
+	if got, want := players[1], (&settings{depth: 2, scale: 0.25, name: "x", seed: 11}); !reflect.DeepEqual(got, want) {
+		t.Errorf("New: Player is %+v, expected %+v", got, want)
+	}
+	game.New(players, game.Michigan()).Play()
+
+	specs, _ = Parse("minor,minor")
+	if players, _ := specs.New(game.Michigan(), 0); players[0] == players[1] {
+		t.Error("New: two minor Players are equal")
+	}
+
+	specs, _ = Parse("test:name=fail")
+	if _, err := specs.New(game.Michigan(), 0); err == nil || err.Error() != "bots: test: failed" {
+		t.Errorf("New: error %v, expected bots: test: failed", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	var names []string
+	for _, b := range List() {
+		names = append(names, b.Name)
+	}
+	if want := []string{"human", "major", "minor", "random", "test"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List() names %v, expected %v", names, want)
+	}
+	if b, ok := Lookup("random"); !ok || b.Options[0].Name != "p" {
+		t.Errorf("Lookup(random) = %+v, %v", b, ok)
+	}
+	if help := Help(); !strings.Contains(help, "depth=2") || !strings.Contains(help, "always leads from the minor suit") {
+		t.Errorf("Help() omits options or descriptions:\n%v", help)
+	}
+}
+
+func TestHuman(t *testing.T) {
+	var out bytes.Buffer
+	in := bufio.NewScanner(strings.NewReader("\nclubs\nS\nhe\n"))
+	h := newHuman(game.Michigan(), in, &out)
+	h.Init(3, 1, []card.Card{3, 30, 40}, []int{3, 3, 3, 3, 3}, 0)
+	state := game.TableState{Stake: []int{3, 3, 3, 3, 3}, Score: []int{0, 0, 0}, Count: []int{3, 2, 3}}
+	if h.PlayMajor(state, card.Black) {
+		t.Errorf("PlayMajor: got major after %q", "clubs")
+	}
+	if !h.PlayMajor(state, card.Black) {
+		t.Errorf("PlayMajor: got minor after %q", "S")
+	}
+	if s := h.ChooseSuit(state, []card.Suit{card.Clubs, card.Hearts}); s != card.Hearts {
+		t.Errorf("ChooseSuit: got %v after %q", s, "he")
+	}
+	if !strings.Contains(out.String(), "Ten 3") {
+		t.Errorf("output omits the stakes:\n%v", out.String())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("PlayMajor: no panic at the end of input")
+		}
+	}()
+	h.PlayMajor(state, card.Black)
+}
+
+func TestRegisterPanics(t *testing.T) {
+	newFixed := func(Options) (game.Player, error) { return &fixed{}, nil }
+	for name, b := range map[string]Bot{
+		"name":      {Name: "a:b", New: newFixed},
+		"empty":     {New: newFixed},
+		"duplicate": {Name: "minor", New: newFixed},
+		"new":       {Name: "nil"},
+		"default":   {Name: "bad", New: newFixed, Options: []Option{{Name: "n", Kind: Int, Default: "x"}}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", name)
+				}
+			}()
+			Register(b)
+		}()
+	}
+}
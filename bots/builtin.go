@@ -0,0 +1,54 @@
+package bots
+
+import (
+	"math/rand"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func init() {
+	Register(Bot{
+		Name: "minor",
+		Help: "always leads from the minor suit",
+		New:  func(Options) (game.Player, error) { return &fixed{major: false}, nil },
+	})
+	Register(Bot{
+		Name: "major",
+		Help: "always leads from the major suit",
+		New:  func(Options) (game.Player, error) { return &fixed{major: true}, nil },
+	})
+	Register(Bot{
+		Name: "random",
+		Help: "leads from a suit at random",
+		Options: []Option{
+			{Name: "p", Kind: Float, Default: "0.5", Help: "the probability of leading from the major suit"},
+		},
+		New: func(opts Options) (game.Player, error) {
+			return &random{p: opts.Float("p"), rng: rand.New(rand.NewSource(opts.Seed))}, nil
+		},
+	})
+}
+
+// fixed is a Player that always leads from the major suit if major is true,
+// and otherwise from the minor suit. Each is distinct, so that a Game may
+// seat more than one.
+type fixed struct{ major bool }
+
+func (*fixed) Init(int, int, []card.Card, []int, int) {}
+
+func (*fixed) Note(int, card.Card) {}
+
+func (f *fixed) PlayMajor(game.TableState, card.Color) bool { return f.major }
+
+// random is a Player that leads from the major suit with probability p.
+type random struct {
+	p   float64
+	rng *rand.Rand
+}
+
+func (*random) Init(int, int, []card.Card, []int, int) {}
+
+func (*random) Note(int, card.Card) {}
+
+func (r *random) PlayMajor(game.TableState, card.Color) bool { return r.rng.Float64() < r.p }
@@ -0,0 +1,107 @@
+package bots
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/playerutil"
+)
+
+// stdin is shared by every human reading from the standard input, since only
+// one Player decides at a time.
+var stdin = bufio.NewScanner(os.Stdin)
+
+func init() {
+	Register(Bot{
+		Name: "human",
+		Help: "asks on the standard input for each decision",
+		New: func(opts Options) (game.Player, error) {
+			return newHuman(opts.Rules, stdin, os.Stdout), nil
+		},
+	})
+}
+
+// human is a Player that asks a person for each decision. It writes its hand
+// and the state of the table to out, and reads the suit from which to lead
+// from in, one line at a time.
+type human struct {
+	rules game.Rules
+	in    *bufio.Scanner
+	out   io.Writer
+
+	playerutil.Hand
+}
+
+// newHuman returns a human that plays according to the given Rules.
+func newHuman(rules game.Rules, in *bufio.Scanner, out io.Writer) *human {
+	return &human{rules: rules, in: in, out: out}
+}
+
+func (h *human) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	h.Hand.Init(n, pos, hand, stake, kitty)
+	fmt.Fprintf(h.out, "You are at position %v of %v, holding %v.\n", pos, n, cards(h.Cards))
+}
+
+func (h *human) PlayMajor(t game.TableState, color card.Color) bool {
+	return h.ask(t, []card.Suit{color.Minor(), color.Major()}) == color.Major()
+}
+
+func (h *human) ChooseSuit(t game.TableState, suits []card.Suit) card.Suit {
+	return h.ask(t, suits)
+}
+
+// ask shows the state of the table and asks for one of the given suits until
+// it reads a line that begins the name of one. ask panics if the input ends.
+func (h *human) ask(t game.TableState, suits []card.Suit) card.Suit {
+	h.show(t)
+	names := make([]string, len(suits))
+	for i, s := range suits {
+		names[i] = s.String()
+	}
+	for {
+		fmt.Fprintf(h.out, "Lead from %v? ", strings.Join(names, " or "))
+		if !h.in.Scan() {
+			if err := h.in.Err(); err != nil {
+				panic("bots: human: " + err.Error())
+			}
+			panic("bots: human: end of input")
+		}
+		answer := strings.ToLower(strings.TrimSpace(h.in.Text()))
+		for _, s := range suits {
+			if answer != "" && strings.HasPrefix(strings.ToLower(s.String()), answer) {
+				return s
+			}
+		}
+	}
+}
+
+// show writes the state of the table and the cards in hand.
+func (h *human) show(t game.TableState) {
+	pots := make([]string, len(h.rules.Layout))
+	for i, p := range h.rules.Layout {
+		pots[i] = fmt.Sprintf("%v %v", p.Name, t.Stake[i])
+	}
+	fmt.Fprintf(h.out, "Pots: %v; Kitty %v\n", strings.Join(pots, ", "), t.Kitty)
+	if h.rules.Trump {
+		fmt.Fprintf(h.out, "Trumps: %v\n", t.Trump)
+	}
+	fmt.Fprintf(h.out, "Scores %v; cards held %v\n", t.Score, t.Count)
+	if len(t.Run) > 0 {
+		fmt.Fprintf(h.out, "The last run was %v.\n", cards(t.Run))
+	}
+	fmt.Fprintf(h.out, "You hold %v.\n", cards(h.Cards))
+}
+
+// cards formats cards by rank and suit.
+func cards(cs []card.Card) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = fmt.Sprintf("%v of %v", c.Rank(), c.Suit())
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
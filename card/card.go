@@ -1,4 +1,4 @@
-// Package card defines a standard deck of 52 playing cards.
+// Package card defines standard playing cards and decks.
 package card
 
 // A Card is a standard playing card. Cards are ordered by suit first, then rank
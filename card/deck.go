@@ -0,0 +1,93 @@
+package card
+
+import "math/rand"
+
+// A Deck is an ordered collection of Cards. The first Card is the top of the
+// Deck. A Deck may omit some Cards or contain more than one copy of a Card.
+type Deck []Card
+
+// NewDeck returns a standard 52-card Deck in order.
+func NewDeck() Deck { return Stripped(Two) }
+
+// Stripped returns an ordered Deck containing the Cards of each Suit ranked low
+// or higher. Stripped(Seven) returns a 32-card piquet deck.
+func Stripped(low Rank) Deck {
+	var d Deck
+	for s := Clubs; s <= Hearts; s++ {
+		for r := low; r <= Ace; r++ {
+			d = append(d, s.Rank(r))
+		}
+	}
+	return d
+}
+
+// Double returns an ordered Deck containing two copies of each Card.
+func Double() Deck { return append(NewDeck(), NewDeck()...) }
+
+// Remove returns a copy of d with one copy of each of the given Cards removed.
+func (d Deck) Remove(cards ...Card) Deck {
+	out := append(Deck(nil), d...)
+	for _, c := range cards {
+		for i := range out {
+			if out[i] == c {
+				out = append(out[:i], out[i+1:]...)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Shuffle randomizes the order of a Deck using the random number generator rng.
+// If rng is nil, Shuffle uses the default Source of the math/rand package.
+func (d Deck) Shuffle(rng *rand.Rand) {
+	swap := func(i, j int) { d[i], d[j] = d[j], d[i] }
+	if rng == nil {
+		rand.Shuffle(len(d), swap)
+		return
+	}
+	rng.Shuffle(len(d), swap)
+}
+
+// Cut moves the top n Cards of a Deck to the bottom, preserving their order.
+// n is taken modulo the length of the Deck.
+func (d Deck) Cut(n int) {
+	if len(d) == 0 {
+		return
+	}
+	n %= len(d)
+	if n < 0 {
+		n += len(d)
+	}
+	top := append(Deck(nil), d[:n]...)
+	copy(d, d[n:])
+	copy(d[len(d)-n:], top)
+}
+
+// Draw removes the top n Cards from a Deck and returns them.
+// If the Deck holds fewer than n Cards, Draw returns all of them, and if n is
+// negative, Draw returns none.
+func (d *Deck) Draw(n int) []Card {
+	if n > len(*d) {
+		n = len(*d)
+	}
+	if n < 0 {
+		n = 0
+	}
+	cards := append([]Card(nil), (*d)[:n]...)
+	*d = (*d)[n:]
+	return cards
+}
+
+// Deal deals the entire Deck one Card at a time into n hands, beginning with
+// the first hand. If n is not positive, Deal returns no hands.
+func (d Deck) Deal(n int) [][]Card {
+	if n <= 0 {
+		return nil
+	}
+	hands := make([][]Card, n)
+	for i, c := range d {
+		hands[i%n] = append(hands[i%n], c)
+	}
+	return hands
+}
@@ -0,0 +1,170 @@
+package card
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNewDeck(t *testing.T) {
+	d := NewDeck()
+	if len(d) != 52 {
+		t.Fatalf("len(NewDeck()) is %v, expected 52", len(d))
+	}
+	for i, c := range d {
+		if c != Card(i) {
+			t.Errorf("NewDeck()[%v] is %v, expected %v", i, c, i)
+		}
+	}
+}
+
+func TestStripped(t *testing.T) {
+	for _, test := range []struct {
+		low  Rank
+		n    int
+		last Card
+	}{
+		{Two, 52, Hearts.Rank(Ace)},
+		{Seven, 32, Hearts.Rank(Ace)},
+		{Ace, 4, Hearts.Rank(Ace)},
+	} {
+		d := Stripped(test.low)
+		if len(d) != test.n {
+			t.Errorf("len(Stripped(%v)) is %v, expected %v",
+				test.low, len(d), test.n,
+			)
+		}
+		for _, c := range d {
+			if c.Rank() < test.low {
+				t.Errorf("Stripped(%v) contains %v", test.low, c)
+			}
+		}
+		if last := d[len(d)-1]; last != test.last {
+			t.Errorf("Stripped(%v): last card is %v, expected %v",
+				test.low, last, test.last,
+			)
+		}
+	}
+}
+
+func TestDouble(t *testing.T) {
+	d := Double()
+	if len(d) != 104 {
+		t.Fatalf("len(Double()) is %v, expected 104", len(d))
+	}
+	count := make(map[Card]int)
+	for _, c := range d {
+		count[c]++
+	}
+	for c := Card(0); c < 52; c++ {
+		if count[c] != 2 {
+			t.Errorf("Double() contains %v copies of %v, expected 2",
+				count[c], c,
+			)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	for name, test := range map[string]struct {
+		d     Deck
+		cards []Card
+		want  Deck
+	}{
+		"none":       {Deck{0, 1, 2}, nil, Deck{0, 1, 2}},
+		"one":        {Deck{0, 1, 2}, []Card{1}, Deck{0, 2}},
+		"absent":     {Deck{0, 1, 2}, []Card{3}, Deck{0, 1, 2}},
+		"one copy":   {Deck{0, 1, 0, 1}, []Card{0}, Deck{1, 0, 1}},
+		"two copies": {Deck{0, 1, 0, 1}, []Card{0, 0}, Deck{1, 1}},
+	} {
+		d := append(Deck(nil), test.d...)
+		if got := d.Remove(test.cards...); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Remove(%q): got %v, expected %v", name, got, test.want)
+		}
+		if !reflect.DeepEqual(d, test.d) {
+			t.Errorf("Remove(%q) modified the Deck: %v", name, d)
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	d := NewDeck()
+	d.Shuffle(rand.New(rand.NewSource(1)))
+	e := NewDeck()
+	e.Shuffle(rand.New(rand.NewSource(1)))
+	if !reflect.DeepEqual(d, e) {
+		t.Errorf("Shuffle with equal seeds: got %v and %v", d, e)
+	}
+	if reflect.DeepEqual(d, NewDeck()) {
+		t.Errorf("Shuffle did not change the order of the Deck")
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	if !reflect.DeepEqual(d, NewDeck()) {
+		t.Errorf("Shuffle changed the Cards in the Deck: %v", d)
+	}
+}
+
+func TestCut(t *testing.T) {
+	for _, test := range []struct {
+		n    int
+		want Deck
+	}{
+		{0, Deck{0, 1, 2, 3, 4}},
+		{2, Deck{2, 3, 4, 0, 1}},
+		{5, Deck{0, 1, 2, 3, 4}},
+		{7, Deck{2, 3, 4, 0, 1}},
+		{-1, Deck{4, 0, 1, 2, 3}},
+	} {
+		d := Deck{0, 1, 2, 3, 4}
+		if d.Cut(test.n); !reflect.DeepEqual(d, test.want) {
+			t.Errorf("Cut(%v): got %v, expected %v", test.n, d, test.want)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	for _, test := range []struct {
+		n     int
+		cards []Card
+		d     Deck
+	}{
+		{0, []Card{}, Deck{0, 1, 2}},
+		{2, []Card{0, 1}, Deck{2}},
+		{3, []Card{0, 1, 2}, Deck{}},
+		{4, []Card{0, 1, 2}, Deck{}},
+		{-1, []Card{}, Deck{0, 1, 2}},
+	} {
+		d := Deck{0, 1, 2}
+		cards := d.Draw(test.n)
+		if len(cards) != len(test.cards) ||
+			len(cards) > 0 && !reflect.DeepEqual(cards, test.cards) {
+			t.Errorf("Draw(%v): got %v, expected %v", test.n, cards, test.cards)
+		}
+		if len(d) != len(test.d) ||
+			len(d) > 0 && !reflect.DeepEqual(d, test.d) {
+			t.Errorf("Draw(%v): Deck is %v, expected %v", test.n, d, test.d)
+		}
+	}
+}
+
+func TestDeal(t *testing.T) {
+	for _, test := range []struct {
+		d     Deck
+		n     int
+		hands [][]Card
+	}{
+		{Deck{0, 1, 2, 3, 4}, 1, [][]Card{{0, 1, 2, 3, 4}}},
+		{Deck{0, 1, 2, 3, 4}, 2, [][]Card{{0, 2, 4}, {1, 3}}},
+		{Deck{0, 1, 2, 3, 4}, 3, [][]Card{{0, 3}, {1, 4}, {2}}},
+		{Deck{0, 1}, 3, [][]Card{{0}, {1}, nil}},
+		{Deck{0, 1}, 0, nil},
+		{Deck{0, 1}, -2, nil},
+	} {
+		if hands := test.d.Deal(test.n); !reflect.DeepEqual(hands, test.hands) {
+			t.Errorf("%v.Deal(%v): got %v, expected %v",
+				test.d, test.n, hands, test.hands,
+			)
+		}
+	}
+}
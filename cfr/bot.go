@@ -0,0 +1,32 @@
+package cfr
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func init() {
+	bots.Register(bots.Bot{
+		Name: "cfr",
+		Help: "leads according to a strategy learned by counterfactual regret minimization",
+		Options: []bots.Option{
+			{Name: "table", Kind: bots.String, Help: "a JSON file holding the strategy Table; without one, each suit is equally likely"},
+		},
+		New: func(opts bots.Options) (game.Player, error) {
+			var tb Table
+			if name := opts.String("table"); name != "" {
+				data, err := os.ReadFile(name)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal(data, &tb); err != nil {
+					return nil, err
+				}
+			}
+			return NewPlayer(tb, opts.Rules, opts.Seed), nil
+		},
+	})
+}
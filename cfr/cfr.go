@@ -0,0 +1,192 @@
+// Package cfr learns strategies for the lead decision of Tripoli by
+// counterfactual regret minimization.
+//
+// A player who must lead from one of the two suits of a color decides with
+// imperfect information: they know their own cards and the cards played, but
+// not the cards of the other players or the widow. The decision is abstracted
+// as an InfoSet, which summarizes what the player has observed, and a Trainer
+// learns, by Monte Carlo CFR with external sampling over hands played by the
+// game engine, a strategy that assigns to each InfoSet a probability of
+// leading from the major suit. The average strategy of the training is a
+// Table, which a Player uses to play.
+package cfr
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/playerutil"
+)
+
+// An InfoSet summarizes the information available to a player at a lead
+// decision. Each array describes the player's cards in the minor and major
+// suits of the decision's color, in that order.
+type InfoSet struct {
+	// Color is the color of the decision.
+	Color card.Color
+
+	// Len is the number of cards held in each suit, up to 4.
+	Len [2]uint8
+
+	// Low is the rank of the lowest card held in each suit: 0 for Two
+	// through Five, 1 for Six through Nine, and 2 for Ten through Ace.
+	Low [2]uint8
+
+	// Seq is the number of consecutive ranks held in each suit beginning
+	// with the lowest card, up to 3.
+	Seq [2]uint8
+
+	// Stake reports whether any card held in each suit collects a Pot that
+	// holds chips.
+	Stake [2]bool
+
+	// Short is the fewest cards held by any other player, up to 3.
+	Short uint8
+}
+
+// Observe returns the InfoSet of a player who holds the given cards and must
+// lead from a suit of the given color, in a game played according to the
+// given Rules.
+func Observe(rules game.Rules, hand []card.Card, t game.TableState, color card.Color) InfoSet {
+	is := InfoSet{Color: color, Short: 3}
+	var stake [52]bool
+	for i, p := range rules.Layout {
+		if i >= len(t.Stake) || t.Stake[i] == 0 {
+			continue
+		}
+		for _, c := range p.Cards {
+			if p.Trump {
+				c = t.Trump.Rank(c.Rank())
+			}
+			stake[c] = true
+		}
+	}
+	for i, s := range [2]card.Suit{color.Minor(), color.Major()} {
+		var held [13]bool
+		for _, c := range hand {
+			if c.Suit() == s {
+				held[c.Rank()] = true
+				is.Len[i]++
+				is.Stake[i] = is.Stake[i] || stake[c]
+			}
+		}
+		if is.Len[i] > 4 {
+			is.Len[i] = 4
+		}
+		low := 0
+		for low < 13 && !held[low] {
+			low++
+		}
+		if low == 13 {
+			continue
+		}
+		is.Low[i] = uint8(low / 4)
+		if low == 12 {
+			is.Low[i] = 2
+		}
+		for r := low; r < 13 && held[r] && is.Seq[i] < 3; r++ {
+			is.Seq[i]++
+		}
+	}
+	for pos, n := range t.Count {
+		if pos != t.Leader && n < int(is.Short) {
+			is.Short = uint8(n)
+		}
+	}
+	return is
+}
+
+// MarshalText encodes an InfoSet as its color, followed by the length, low
+// rank, sequence and stake of each suit and the fewest cards held by another
+// player, as in "Red 3121 1010 2".
+func (is InfoSet) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	b.WriteString(is.Color.String())
+	for i := range is.Len {
+		stake := 0
+		if is.Stake[i] {
+			stake = 1
+		}
+		fmt.Fprintf(&b, " %d%d%d%d", is.Len[i], is.Low[i], is.Seq[i], stake)
+	}
+	fmt.Fprintf(&b, " %d", is.Short)
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText decodes an InfoSet encoded by MarshalText.
+func (is *InfoSet) UnmarshalText(text []byte) error {
+	f := strings.Fields(string(text))
+	if len(f) != 4 || len(f[1]) != 4 || len(f[2]) != 4 || len(f[3]) != 1 {
+		return fmt.Errorf("cfr: malformed InfoSet %q", text)
+	}
+	var v InfoSet
+	switch f[0] {
+	case card.Black.String():
+		v.Color = card.Black
+	case card.Red.String():
+		v.Color = card.Red
+	default:
+		return fmt.Errorf("cfr: malformed InfoSet %q", text)
+	}
+	var stake [2]uint8
+	fields := []struct {
+		d   *uint8
+		b   byte
+		max uint8
+	}{
+		{&v.Len[0], f[1][0], 4}, {&v.Low[0], f[1][1], 2}, {&v.Seq[0], f[1][2], 3}, {&stake[0], f[1][3], 1},
+		{&v.Len[1], f[2][0], 4}, {&v.Low[1], f[2][1], 2}, {&v.Seq[1], f[2][2], 3}, {&stake[1], f[2][3], 1},
+		{&v.Short, f[3][0], 3},
+	}
+	for _, x := range fields {
+		if x.b < '0' || x.b-'0' > x.max {
+			return fmt.Errorf("cfr: malformed InfoSet %q", text)
+		}
+		*x.d = x.b - '0'
+	}
+	v.Stake = [2]bool{stake[0] == 1, stake[1] == 1}
+	*is = v
+	return nil
+}
+
+// A Table is a strategy for the lead decision. It records for each InfoSet the
+// probability of leading from the major suit. A Table may be encoded and
+// decoded with the encoding/json package.
+type Table map[InfoSet]float64
+
+// Major returns the probability of leading from the major suit at an InfoSet.
+// It is 1/2 for an InfoSet that is not in the Table.
+func (tb Table) Major(is InfoSet) float64 {
+	if p, ok := tb[is]; ok {
+		return p
+	}
+	return 0.5
+}
+
+// A Player is a game.Player that decides according to a Table.
+type Player struct {
+	table Table
+	rules game.Rules
+	rng   *rand.Rand
+
+	playerutil.Hand
+}
+
+// NewPlayer returns a Player that plays according to the given Table in games
+// played according to the given Rules, drawing its decisions from a random
+// number generator seeded with the given value.
+func NewPlayer(table Table, rules game.Rules, seed int64) *Player {
+	return &Player{
+		table: table,
+		rules: rules,
+		rng:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// PlayMajor implements game.Player.
+func (p *Player) PlayMajor(t game.TableState, color card.Color) bool {
+	return p.rng.Float64() < p.table.Major(Observe(p.rules, p.Cards, t, color))
+}
@@ -0,0 +1,179 @@
+package cfr
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func TestObserve(t *testing.T) {
+	hand := []card.Card{
+		card.Clubs.Rank(card.Five),
+		card.Diamonds.Rank(card.Two), card.Diamonds.Rank(card.Three),
+		card.Diamonds.Rank(card.Four), card.Diamonds.Rank(card.Nine),
+		card.Diamonds.Rank(card.Jack),
+		card.Hearts.Rank(card.Queen), card.Hearts.Rank(card.King),
+	}
+	for name, test := range map[string]struct {
+		stake []int
+		count []int
+		color card.Color
+		want  InfoSet
+	}{
+		"red": {
+			[]int{0, 0, 3, 0, 0}, []int{8, 2, 7}, card.Red,
+			InfoSet{card.Red, [2]uint8{4, 2}, [2]uint8{0, 2}, [2]uint8{3, 2}, [2]bool{false, true}, 2},
+		},
+		"unstaked": {
+			[]int{1, 1, 0, 0, 1}, []int{8, 5, 9}, card.Red,
+			InfoSet{card.Red, [2]uint8{4, 2}, [2]uint8{0, 2}, [2]uint8{3, 2}, [2]bool{false, false}, 3},
+		},
+		"black": {
+			[]int{0, 0, 3, 0, 0}, []int{8, 1, 1}, card.Black,
+			InfoSet{card.Black, [2]uint8{1, 0}, [2]uint8{0, 0}, [2]uint8{1, 0}, [2]bool{false, false}, 1},
+		},
+	} {
+		ts := game.TableState{Stake: test.stake, Count: test.count}
+		if got := Observe(game.Michigan(), hand, ts, test.color); got != test.want {
+			t.Errorf("Observe(%q) = %+v, expected %+v", name, got, test.want)
+		}
+	}
+}
+
+func TestInfoSetText(t *testing.T) {
+	is := InfoSet{card.Red, [2]uint8{3, 1}, [2]uint8{1, 0}, [2]uint8{2, 1}, [2]bool{true, false}, 2}
+	text, err := is.MarshalText()
+	if err != nil || string(text) != "Red 3121 1010 2" {
+		t.Errorf("MarshalText(%+v) = %q, %v, expected %q", is, text, err, "Red 3121 1010 2")
+	}
+	var got InfoSet
+	if err := got.UnmarshalText(text); err != nil || got != is {
+		t.Errorf("UnmarshalText(%q) = %+v, %v, expected %+v", text, got, err, is)
+	}
+	for _, s := range []string{"", "Red 3121 1010", "Blue 3121 1010 2", "Red 5121 1010 2", "Red 3121 1012 2", "Red 3121 1010 x"} {
+		if err := got.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("UnmarshalText(%q): expected an error", s)
+		}
+	}
+}
+
+func TestTrain(t *testing.T) {
+	rules := game.Michigan()
+	a, b := NewTrainer(rules, 3, 1), NewTrainer(rules, 3, 1)
+	a.Train(300)
+	b.Train(300)
+	tb := a.Strategy()
+	if len(tb) == 0 {
+		t.Fatal("Strategy is empty after training")
+	}
+	if !reflect.DeepEqual(tb, b.Strategy()) {
+		t.Error("Trainers with the same seed learned different strategies")
+	}
+	for is, p := range tb {
+		if p < 0 || p > 1 {
+			t.Errorf("Strategy: %+v has probability %v", is, p)
+		}
+	}
+
+	data, err := json.Marshal(tb)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var loaded Table
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, tb) {
+		t.Error("Table differs after encoding and decoding")
+	}
+
+	players := make([]game.Player, 3)
+	for i := range players {
+		players[i] = NewPlayer(loaded, rules, int64(i))
+	}
+	g := game.New(players, rules)
+	for i := 0; i < 20; i++ {
+		g.Play()
+	}
+}
+
+func TestBegin(t *testing.T) {
+	tr := NewTrainer(game.Michigan(), 3, 1)
+	first := game.New(tr.players, tr.rules).Begin().State().Stake
+	var later int
+	for seed := int64(0); seed < 20; seed++ {
+		state := tr.begin(seed).State()
+		if !reflect.DeepEqual(tr.begin(seed).State(), state) {
+			t.Errorf("begin(%v): hands differ", seed)
+		}
+		if !reflect.DeepEqual(state.Stake, first) {
+			later++
+		}
+	}
+	if later == 0 {
+		t.Errorf("begin: every hand has the stakes %v", first)
+	}
+}
+
+func TestRun(t *testing.T) {
+	reports := NewTrainer(game.Michigan(), 4, 1).Run(250, 100, 10)
+	var got []int
+	for _, r := range reports {
+		got = append(got, r.Iterations)
+	}
+	if want := []int{100, 200, 250}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Run reported after %v iterations, expected %v", got, want)
+	}
+	for _, r := range reports {
+		if math.IsNaN(r.Exploitability) || math.IsInf(r.Exploitability, 0) || r.Exploitability < 0 {
+			t.Errorf("Run: exploitability %v after %v iterations", r.Exploitability, r.Iterations)
+		}
+	}
+
+	// With enough hands, the estimate falls as training goes on.
+	reports = NewTrainer(game.Michigan(), 4, 1).Run(1000, 500, 100)
+	if first, last := reports[0], reports[len(reports)-1]; last.Exploitability >= first.Exploitability {
+		t.Errorf("Run: exploitability rose from %v after %v iterations to %v after %v",
+			first.Exploitability, first.Iterations, last.Exploitability, last.Iterations)
+	}
+}
+
+func TestBot(t *testing.T) {
+	tb := Table{InfoSet{Color: card.Red, Short: 3}: 1}
+	data, err := json.Marshal(tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "table.json")
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	specs, err := bots.Parse("cfr:table=" + name + ",cfr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	players, err := specs.New(game.Michigan(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := players[0].(*Player); !reflect.DeepEqual(p.table, tb) {
+		t.Errorf("table is %v, expected %v", p.table, tb)
+	}
+	if p := players[1].(*Player); p.table != nil {
+		t.Errorf("table is %v, expected none", p.table)
+	}
+}
+
+func BenchmarkTrain(b *testing.B) {
+	t := NewTrainer(game.Michigan(), 4, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	t.Train(b.N)
+}
@@ -0,0 +1,305 @@
+package cfr
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Trainer learns a strategy for the lead decision by Monte Carlo CFR with
+// external sampling. Each iteration deals a hand and traverses it from the
+// point of view of one position, taken in turn: every action of that position
+// is explored, and the decisions of the others and the deal are sampled.
+// Every player follows the strategy being learned. The hand is drawn from
+// across a game, so that the stakes and the Kitty vary as they do in play.
+// A Trainer must not be used by more than one goroutine at a time.
+type Trainer struct {
+	rules   game.Rules
+	players []game.Player
+	rng     *rand.Rand
+
+	// nodes records the regrets and the cumulative strategy at each InfoSet.
+	nodes map[InfoSet]*node
+
+	iterations int
+}
+
+// A node records the cumulative regret of each action at an InfoSet, and the
+// sum over iterations of the probability of each action.
+type node struct {
+	regret, sum [2]float64
+}
+
+// strategy returns the probability of each action by regret matching.
+func (nd *node) strategy() [2]float64 {
+	r := [2]float64{nd.regret[0], nd.regret[1]}
+	for a := range r {
+		if r[a] < 0 {
+			r[a] = 0
+		}
+	}
+	if total := r[0] + r[1]; total > 0 {
+		return [2]float64{r[0] / total, r[1] / total}
+	}
+	return [2]float64{0.5, 0.5}
+}
+
+// seat is the Player that occupies each seat of a Trainer's games. Its
+// decisions are made by the Trainer. Its index distinguishes the seats, since
+// the Players of a Game must be distinct.
+type seat struct{ index int }
+
+func (seat) Init(int, int, []card.Card, []int, int) {}
+
+func (seat) Note(int, card.Card) {}
+
+func (seat) PlayMajor(game.TableState, card.Color) bool { panic("cfr: seat asked to decide") }
+
+// NewTrainer returns a Trainer for n players that play according to the given
+// Rules, drawing its deals and samples from a random number generator seeded
+// with the given value. NewTrainer panics if the number of players is not
+// supported.
+func NewTrainer(rules game.Rules, n int, seed int64) *Trainer {
+	t := &Trainer{
+		rules:   rules,
+		players: make([]game.Player, n),
+		rng:     rand.New(rand.NewSource(seed)),
+		nodes:   make(map[InfoSet]*node),
+	}
+	for i := range t.players {
+		t.players[i] = seat{i}
+	}
+	// Validate the table once; each iteration plays a new Game.
+	game.New(t.players, rules)
+	return t
+}
+
+// Iterations returns the number of iterations of training.
+func (t *Trainer) Iterations() int { return t.iterations }
+
+// Train runs the given number of iterations of training.
+func (t *Trainer) Train(iterations int) {
+	for i := 0; i < iterations; i++ {
+		h := t.begin(t.rng.Int63())
+		pos := t.iterations % len(t.players)
+		t.traverse(h, pos, h.State().Score[pos])
+		t.iterations++
+	}
+}
+
+// handsPerGame bounds the number of hands that begin plays before the hand
+// that it begins.
+const handsPerGame = 10
+
+// begin begins a hand of a new Game seeded with the given value. It first
+// plays fewer than handsPerGame hands, as many as the seed determines, in
+// which each decision is made at random, so that the stakes and the Kitty
+// carry over from earlier hands as they do in play. The hand depends only on
+// the seed.
+func (t *Trainer) begin(seed int64) *game.Hand {
+	g := game.New(t.players, t.rules)
+	g.Seed(seed)
+	rng := rand.New(rand.NewSource(seed))
+	for n := rng.Intn(handsPerGame); n > 0; n-- {
+		h := g.Begin()
+		for e, ok := h.Next(); ok; e, ok = h.Next() {
+			if e.Kind == game.DecisionEvent {
+				h.Decide(action(e.Suits[0].Color(), rng.Intn(2) == 1))
+			}
+		}
+	}
+	return g.Begin()
+}
+
+// traverse plays a hand out, exploring each action of the player at position
+// pos, and returns the expected number of chips that they gain relative to
+// the given score.
+func (t *Trainer) traverse(h *game.Hand, pos, score int) float64 {
+	for {
+		e, ok := h.Next()
+		if !ok {
+			return float64(h.State().Score[pos] - score)
+		}
+		if e.Kind != game.DecisionEvent {
+			continue
+		}
+		is := t.observe(h, e)
+		nd := t.nodes[is]
+		if nd == nil {
+			nd = &node{}
+			t.nodes[is] = nd
+		}
+		sigma := nd.strategy()
+		if e.Pos != pos {
+			for a := range sigma {
+				nd.sum[a] += sigma[a]
+			}
+			h.Decide(action(is.Color, t.rng.Float64() < sigma[1]))
+			continue
+		}
+		var u [2]float64
+		for a := range u {
+			c := h.Clone(nil)
+			c.Decide(action(is.Color, a == 1))
+			u[a] = t.traverse(c, pos, score)
+		}
+		v := sigma[0]*u[0] + sigma[1]*u[1]
+		for a := range u {
+			nd.regret[a] += u[a] - v
+		}
+		return v
+	}
+}
+
+// observe returns the InfoSet of the player who must make a decision.
+func (t *Trainer) observe(h *game.Hand, e game.Event) InfoSet {
+	return Observe(t.rules, h.Cards(e.Pos), h.State(), e.Suits[0].Color())
+}
+
+// action returns the suit of a color from which to lead.
+func action(color card.Color, major bool) card.Suit {
+	if major {
+		return color.Major()
+	}
+	return color.Minor()
+}
+
+// Strategy returns the average strategy of the training.
+func (t *Trainer) Strategy() Table {
+	tb := make(Table, len(t.nodes))
+	for is, nd := range t.nodes {
+		if total := nd.sum[0] + nd.sum[1]; total > 0 {
+			tb[is] = nd.sum[1] / total
+		}
+	}
+	return tb
+}
+
+// Exploitability estimates how much a player could gain by deviating from
+// the average strategy of the training while the others follow it. It first
+// plays the given number of hands, and at each decision of each position
+// plays the hand out after each action to estimate the value of each action
+// at each InfoSet. A best response takes at each InfoSet the action whose
+// estimated value is greater. Exploitability then plays as many other hands,
+// and returns the mean gain in chips per hand of a player who plays the best
+// response over one who follows the strategy, or 0 if the best response gains
+// nothing, since following the strategy is itself a response.
+//
+// The best response is estimated from samples and is restricted to what the
+// InfoSets distinguish, so the estimate tends to understate how exploitable
+// the strategy is, and is itself subject to sampling error. The hands are the
+// same in each call, so that estimates made at different points in the
+// training are comparable.
+func (t *Trainer) Exploitability(hands int) float64 {
+	tb := t.Strategy()
+	values := make(map[InfoSet]*[2]float64)
+	for i := 0; i < hands; i++ {
+		for pos := range t.players {
+			h := t.begin(int64(i))
+			t.estimate(h, tb, rand.New(rand.NewSource(int64(i))), pos, h.State().Score[pos], values)
+		}
+	}
+	br := make(Table, len(values))
+	for is, u := range values {
+		if u[1] > u[0] {
+			br[is] = 1
+		} else {
+			br[is] = 0
+		}
+	}
+	var gain float64
+	for i := hands; i < 2*hands; i++ {
+		for pos := range t.players {
+			// Decisions are drawn from the same sequence in both hands, so
+			// that they differ only through the deviation.
+			seed := int64(i)
+			h := t.begin(seed)
+			score := h.State().Score[pos]
+			gain -= t.play(h, tb, rand.New(rand.NewSource(seed)), pos, tb, score)
+			gain += t.play(t.begin(seed), tb, rand.New(rand.NewSource(seed)), pos, br, score)
+		}
+	}
+	return math.Max(gain/float64(hands*len(t.players)), 0)
+}
+
+// estimate plays a hand out according to a Table, drawing decisions from rng,
+// and adds to values the chips gained by the player at position pos relative
+// to the given score after each action at each of their decisions.
+func (t *Trainer) estimate(h *game.Hand, tb Table, rng *rand.Rand, pos, score int, values map[InfoSet]*[2]float64) {
+	for {
+		e, ok := h.Next()
+		if !ok {
+			return
+		}
+		if e.Kind != game.DecisionEvent {
+			continue
+		}
+		is := t.observe(h, e)
+		if e.Pos == pos {
+			u := values[is]
+			if u == nil {
+				u = new([2]float64)
+				values[is] = u
+			}
+			// Each action's rollout draws its decisions from the same
+			// sequence, so that the rollouts differ only through the action.
+			seed := rng.Int63()
+			for a := range u {
+				c := h.Clone(nil)
+				c.Decide(action(is.Color, a == 1))
+				u[a] += t.play(c, tb, rand.New(rand.NewSource(seed)), pos, tb, score)
+			}
+		}
+		h.Decide(action(is.Color, rng.Float64() < tb.Major(is)))
+	}
+}
+
+// play plays a hand out, drawing decisions from rng, and returns the chips
+// gained by the player at position pos relative to the given score. That
+// player plays according to own, and the others according to tb.
+func (t *Trainer) play(h *game.Hand, tb Table, rng *rand.Rand, pos int, own Table, score int) float64 {
+	for {
+		e, ok := h.Next()
+		if !ok {
+			return float64(h.State().Score[pos] - score)
+		}
+		if e.Kind != game.DecisionEvent {
+			continue
+		}
+		is := t.observe(h, e)
+		p := tb.Major(is)
+		if e.Pos == pos {
+			p = own.Major(is)
+		}
+		h.Decide(action(is.Color, rng.Float64() < p))
+	}
+}
+
+// A Report records an estimate of the exploitability of the average strategy
+// after some number of iterations of training.
+type Report struct {
+	Iterations     int
+	Exploitability float64
+}
+
+// Run runs the given number of iterations of training, and after each
+// multiple of every iterations estimates the exploitability of the average
+// strategy over the given number of hands. If every is not positive, the
+// estimate is made only at the end.
+func (t *Trainer) Run(iterations, every, hands int) []Report {
+	if every <= 0 {
+		every = iterations
+	}
+	var reports []Report
+	for i := 0; i < iterations; i += every {
+		n := every
+		if iterations-i < n {
+			n = iterations - i
+		}
+		t.Train(n)
+		reports = append(reports, Report{t.iterations, t.Exploitability(hands)})
+	}
+	return reports
+}
@@ -0,0 +1,87 @@
+// Command abtest compares a candidate Player with a baseline Player by playing
+// duplicate deals until a sequential probability ratio test on the difference
+// in chips won reaches a decision. It reports the test's progress, the mean
+// difference in chips per pair of hands and the implied difference in Elo
+// rating, with 95% confidence intervals.
+//
+// Usage:
+//
+//	abtest -candidate=cfr:table=strategy.json -baseline=minor [flags]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dkmccandless/tripoli/abtest"
+	"github.com/dkmccandless/tripoli/bots"
+	_ "github.com/dkmccandless/tripoli/cfr"
+	"github.com/dkmccandless/tripoli/game"
+	_ "github.com/dkmccandless/tripoli/nn"
+	_ "github.com/dkmccandless/tripoli/script"
+	_ "github.com/dkmccandless/tripoli/tune"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("abtest: ")
+	var candidate, baseline bots.Specs
+	flag.Var(&candidate, "candidate", "the candidate `player`")
+	flag.Var(&baseline, "baseline", "the baseline `player`")
+	var (
+		name     = flag.String("rules", "michigan", "the rules: michigan, newmarket or popejoan")
+		n        = flag.Int("players", 4, "the number of players at the table")
+		mu0      = flag.Float64("mu0", 0, "the mean difference in chips per pair under H0")
+		mu1      = flag.Float64("mu1", 0.1, "the mean difference in chips per pair under H1")
+		alpha    = flag.Float64("alpha", 0.05, "the probability of accepting H1 when H0 holds")
+		beta     = flag.Float64("beta", 0.05, "the probability of accepting H0 when H1 holds")
+		maxPairs = flag.Int("max", 1000000, "the number of pairs after which to stop without a decision")
+		seed     = flag.Int64("seed", 1, "the seed of the deals and players")
+		every    = flag.Int("every", 10000, "the number of pairs between progress reports, or 0 for none")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: abtest -candidate=player -baseline=player [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nPlayers:\n%v", bots.Help())
+	}
+	flag.Parse()
+	if len(candidate) != 1 || len(baseline) != 1 || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	r, err := game.RulesNamed(*name, *n)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := abtest.Config{
+		Rules:     r,
+		Players:   *n,
+		Candidate: factory(candidate[0], r),
+		Baseline:  factory(baseline[0], r),
+		Test:      abtest.SPRT{Mu0: *mu0, Mu1: *mu1, Alpha: *alpha, Beta: *beta},
+		MaxPairs:  *maxPairs,
+		Seed:      *seed,
+		Every:     *every,
+		Progress:  func(r abtest.Report) { fmt.Println(r) },
+	}
+	fmt.Printf("%v vs %v, %v players, H0: %+g, H1: %+g chips per pair\n", candidate, baseline, *n, *mu0, *mu1)
+	fmt.Println(abtest.Run(cfg))
+}
+
+// factory returns a function that returns new Players as described by a Spec.
+// It exits if the Spec's Player cannot be constructed.
+func factory(spec bots.Spec, r game.Rules) func(seed int64) game.Player {
+	if _, err := spec.New(r, 0); err != nil {
+		log.Fatal(err)
+	}
+	return func(seed int64) game.Player {
+		p, err := spec.New(r, seed)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return p
+	}
+}
@@ -0,0 +1,163 @@
+// Command nntrain trains the neural network of the nn player. It simulates
+// hands, recording at each decision between the minor and major suit the
+// chips gained after each choice, and fits a network to choose the better
+// one. It reports the network's regret, the chips per decision that it loses
+// relative to the better choice, on Samples held out from training.
+//
+// Usage:
+//
+//	nntrain -o network.json [flags]
+//
+// The network may be played as nn:weights=network.json. Given -policy, the
+// simulated players decide according to an earlier network instead of at
+// random, so that networks may be trained in rounds.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/env"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/nn"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("nntrain: ")
+	var (
+		name     = flag.String("rules", "michigan", "the rules: michigan, newmarket or popejoan")
+		n        = flag.Int("players", 4, "the number of players at the table")
+		hands    = flag.Int("hands", 20000, "the number of hands to simulate")
+		rollouts = flag.Int("rollouts", 4, "the number of rollouts of each choice at each decision")
+		policy   = flag.String("policy", "", "the `file` of a network by which the simulated players decide, instead of at random")
+		samples  = flag.String("samples", "", "a `file` from which to read the samples, if it exists, or to which to write them")
+		hidden   = flag.String("hidden", "64,32", "the sizes of the hidden layers")
+		epochs   = flag.Int("epochs", 20, "the number of passes over the samples")
+		rate     = flag.Float64("rate", 1e-3, "the learning rate")
+		batch    = flag.Int("batch", 64, "the number of samples of each step")
+		holdout  = flag.Float64("holdout", 0.1, "the fraction of the samples held out to measure regret")
+		seed     = flag.Int64("seed", 1, "the seed of the simulation and training")
+		out      = flag.String("o", "network.json", "the `file` to which to write the network, as JSON if its name ends in .json and otherwise in binary")
+	)
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	r, err := game.RulesNamed(*name, *n)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *holdout <= 0 || *holdout >= 1 {
+		log.Fatalf("holdout %v is outside the range (0, 1)", *holdout)
+	}
+	sizes, err := parseSizes(*hidden)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := nn.Config{Rules: r, Players: *n, Hands: *hands, Rollouts: *rollouts, Seed: *seed}
+	if *policy != "" {
+		if cfg.Policy, err = nn.Load(*policy); err != nil {
+			log.Fatal(err)
+		}
+		if cfg.Policy.Inputs() != env.Size(r) {
+			log.Fatalf("%v: network has %v inputs, expected %v", *policy, cfg.Policy.Inputs(), env.Size(r))
+		}
+	}
+	data, err := load(*samples, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(data) < 2 {
+		log.Fatalf("%v samples are too few to train on", len(data))
+	}
+	// The samples come in order of the games they were drawn from, so they
+	// are shuffled before any are held out.
+	rand.New(rand.NewSource(*seed)).Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+	held := int(float64(len(data)) * *holdout)
+	if held < 1 {
+		held = 1
+	}
+	train, test := data[held:], data[:held]
+	fmt.Printf("%v samples, %v held out\n", len(data), len(test))
+	minor, major := baselines(test)
+	fmt.Printf("regret: always minor %.4f, always major %.4f\n", minor, major)
+
+	net := nn.New(env.Size(r), sizes, *seed)
+	t := nn.NewTrainer(net, *seed)
+	t.Rate, t.Batch = *rate, *batch
+	for e := 1; e <= *epochs; e++ {
+		loss := t.Epoch(train)
+		fmt.Printf("epoch %3d: loss %.4f, regret %.4f\n", e, loss, nn.Regret(net, test))
+	}
+	if err := net.Save(*out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load returns the samples read from the named file if it exists, and
+// otherwise generates them according to cfg and writes them to the file,
+// unless name is empty.
+func load(name string, cfg nn.Config) ([]nn.Sample, error) {
+	if name != "" {
+		f, err := os.Open(name)
+		switch {
+		case err == nil:
+			defer f.Close()
+			return nn.ReadSamples(f)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+	}
+	samples := nn.Generate(cfg)
+	if name == "" {
+		return samples, nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := nn.WriteSamples(f, samples); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return samples, f.Close()
+}
+
+// baselines returns the regret of always leading from the minor suit and of
+// always leading from the major suit.
+func baselines(samples []nn.Sample) (minor, major float64) {
+	for _, s := range samples {
+		if s.Gain[0] > s.Gain[1] {
+			major += float64(s.Gain[0] - s.Gain[1])
+		} else {
+			minor += float64(s.Gain[1] - s.Gain[0])
+		}
+	}
+	return minor / float64(len(samples)), major / float64(len(samples))
+}
+
+// parseSizes parses a comma-separated list of layer sizes.
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid layer size %q", f)
+		}
+		sizes = append(sizes, n)
+	}
+	return sizes, nil
+}
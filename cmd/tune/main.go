@@ -0,0 +1,100 @@
+// Command tune tunes the weights of the linear player by SPSA, playing matches
+// of duplicate deals between perturbed weights on every CPU. It prints the
+// weights after each iteration and saves them to a checkpoint file, from which
+// a later run resumes.
+//
+// Usage:
+//
+//	tune -checkpoint=linear.json [flags]
+//
+// The tuned weights may be played as, for example,
+//
+//	linear:counter=1.2,stake=0.4,run=0.9,len=0.1,low=-0.05
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/tune"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("tune: ")
+	var (
+		name       = flag.String("rules", "michigan", "the rules: michigan, newmarket or popejoan")
+		n          = flag.Int("players", 4, "the number of players at the table")
+		pairs      = flag.Int("pairs", 2000, "the number of pairs of hands of each iteration")
+		workers    = flag.Int("workers", 0, "the number of goroutines that play hands, or 0 for one per CPU")
+		iterations = flag.Int("n", 100, "the number of iterations to run")
+		seed       = flag.Int64("seed", 1, "the seed of a new run")
+		checkpoint = flag.String("checkpoint", "", "the checkpoint `file` to resume from, if it exists, and to save to")
+	)
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	r, err := game.RulesNamed(*name, *n)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m := tune.Match{
+		Rules:   r,
+		Players: *n,
+		New: func(params []float64, seed int64) game.Player {
+			p, err := linear(params).New(r, seed)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return p
+		},
+		Pairs:   *pairs,
+		Workers: *workers,
+	}
+	s := tune.NewSPSA(tune.DefaultWeights, m.Compare, *seed)
+	if *checkpoint != "" {
+		resumed, err := tune.Resume(*checkpoint, len(tune.Features), m.Compare)
+		switch {
+		case err == nil:
+			s = resumed
+			fmt.Printf("resuming %v at iteration %v\n", *checkpoint, s.Iteration())
+		case !errors.Is(err, fs.ErrNotExist):
+			log.Fatal(err)
+		}
+	}
+	err = s.Run(*iterations, *checkpoint, func(s *tune.SPSA) {
+		fmt.Printf("%6d linear:%v\n", s.Iteration(), spec(s.Params()))
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// linear returns the Spec of a linear player with the given weights.
+func linear(weights []float64) bots.Spec {
+	opts := make(map[string]string, len(weights))
+	for i, w := range weights {
+		opts[tune.Features[i]] = strconv.FormatFloat(w, 'g', -1, 64)
+	}
+	return bots.Spec{Name: "linear", Options: opts}
+}
+
+// spec returns the options of a linear player with the given weights.
+func spec(weights []float64) string {
+	opts := make([]string, len(weights))
+	for i, w := range weights {
+		opts[i] = fmt.Sprintf("%v=%.4g", tune.Features[i], w)
+	}
+	return strings.Join(opts, ",")
+}
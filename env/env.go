@@ -0,0 +1,269 @@
+// Package env provides a reinforcement-learning environment in which an agent
+// plays one seat of a game of Tripoli against other Players.
+//
+// Each episode is one hand. The agent's only decisions are those of
+// Player.PlayMajor: whether to lead from the minor or major suit of a color.
+// Observations are fixed-length feature vectors, laid out as follows, where P
+// is the number of Pots in the Rules' Layout:
+//
+//	[0, 52)          the number of copies of each card in the agent's hand
+//	[52, 104)        the number of copies of each card played in the hand
+//	[104, 104+P)     the stake of each Pot
+//	104+P            the Kitty
+//	[105+P, 113+P)   the number of cards held by each other player, beginning
+//	                 at the agent's left, padded with zeros
+//	[113+P, 115+P)   the color of the pending decision, as a one-hot vector
+//	[115+P, 119+P)   the trump suit, as a one-hot vector, if the Rules turn
+//	                 up trumps
+//
+// An Env is deterministic: an episode depends only on its seed and the
+// agent's actions, provided that the other Players are deterministic.
+package env
+
+import (
+	"sync"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+const (
+	// Minor and Major are the actions that lead from the minor and major
+	// suit of the pending decision's color.
+	Minor = 0
+	Major = 1
+)
+
+// maxHands is the number of hands that Reset deals in search of a decision
+// by the agent before giving up.
+const maxHands = 1000
+
+// An Env is an environment in which an agent plays against other Players.
+// An Env must not be used by more than one goroutine at a time.
+type Env struct {
+	rules   game.Rules
+	players []game.Player
+	agent   *agent
+
+	h    *game.Hand
+	pos  int
+	done bool
+
+	// score is the agent's score when the last observation was made.
+	score int
+
+	// color is the color of the pending decision.
+	color card.Color
+
+	obs []float32
+}
+
+// agent is the Player that represents the agent. It is never asked to decide.
+type agent struct{}
+
+func (*agent) Init(int, int, []card.Card, []int, int) {}
+
+func (*agent) Note(int, card.Card) {}
+
+func (*agent) PlayMajor(game.TableState, card.Color) bool { return false }
+
+// New returns an Env in which the agent plays according to the given Rules
+// against the given Players, which must not be used elsewhere.
+// New panics if the number of players is not supported.
+func New(rules game.Rules, opponents []game.Player) *Env {
+	a := &agent{}
+	players := append([]game.Player{a}, opponents...)
+	// Validate the table once; each episode plays a new Game.
+	game.New(players, rules)
+	return &Env{
+		rules:   rules,
+		players: players,
+		agent:   a,
+		obs:     make([]float32, Size(rules)),
+	}
+}
+
+// Size returns the length of the observations of an Env played according to
+// the given Rules.
+func Size(rules game.Rules) int { return 119 + len(rules.Layout) }
+
+// Reset begins an episode with a new Game seeded with the given value, and
+// returns the observation at the agent's first decision. Hands in which the
+// agent has no decision are played out without it and are not part of the
+// episode. The returned slice is reused by the next call to Reset or Step.
+func (e *Env) Reset(seed int64) []float32 {
+	g := game.New(e.players, e.rules)
+	g.Seed(seed)
+	for i := 0; i < maxHands; i++ {
+		e.h = g.Begin()
+		for e.pos = 0; e.h.Player(e.pos) != e.agent; e.pos++ {
+		}
+		e.done = false
+		if e.advance() {
+			t := e.h.State()
+			e.score = t.Score[e.pos]
+			return e.observe(t)
+		}
+	}
+	panic("env: the agent has no decision to make")
+}
+
+// Step takes an action, Minor or Major, at the agent's pending decision, and
+// returns the observation at the agent's next decision or at the end of the
+// episode, the chips that the agent gained since the last observation, and
+// whether the episode is done. The returned slice is reused by the next call
+// to Reset or Step. Step panics if the episode is done.
+func (e *Env) Step(action int) (obs []float32, reward float64, done bool) {
+	if e.done || e.h == nil {
+		panic("env: Step called with no episode in progress")
+	}
+	if action == Major {
+		e.h.Decide(e.color.Major())
+	} else {
+		e.h.Decide(e.color.Minor())
+	}
+	e.done = !e.advance()
+	t := e.h.State()
+	reward = float64(t.Score[e.pos] - e.score)
+	e.score = t.Score[e.pos]
+	return e.observe(t), reward, e.done
+}
+
+// advance plays the hand until the agent must decide or the hand ends,
+// and reports whether the agent must decide.
+func (e *Env) advance() bool {
+	for {
+		ev, ok := e.h.Next()
+		if !ok {
+			return false
+		}
+		if ev.Kind != game.DecisionEvent {
+			continue
+		}
+		if ev.Pos == e.pos {
+			e.color = ev.Suits[0].Color()
+			return true
+		}
+		e.h.Decide(decide(e.h, ev))
+	}
+}
+
+// decide returns the suit chosen at a decision by the Player who must make it.
+func decide(h *game.Hand, ev game.Event) card.Suit {
+	p := h.Player(ev.Pos)
+	color := ev.Suits[0].Color()
+	for _, s := range ev.Suits {
+		if s.Color() != color {
+			return p.(game.SuitChooser).ChooseSuit(h.State(), ev.Suits)
+		}
+	}
+	if p.PlayMajor(h.State(), color) {
+		return color.Major()
+	}
+	return color.Minor()
+}
+
+// observe encodes an observation of the hand.
+func (e *Env) observe(t game.TableState) []float32 {
+	return Observe(e.obs, e.rules, e.pos, e.h.Cards(e.pos), t, e.color, !e.done)
+}
+
+// Observe encodes in obs, which must have length Size(rules), the observation
+// of the player at position pos, who holds the given cards, of a hand whose
+// public state is t. If pending is true, the observation includes a pending
+// decision in the given color. Observe returns obs.
+//
+// Observe lets a Player decide from the same observations as an agent in an
+// Env.
+func Observe(obs []float32, rules game.Rules, pos int, hand []card.Card, t game.TableState, color card.Color, pending bool) []float32 {
+	for i := range obs {
+		obs[i] = 0
+	}
+	for _, c := range hand {
+		obs[c]++
+	}
+	for _, c := range t.Played {
+		obs[52+c]++
+	}
+	i := 104
+	for _, n := range t.Stake {
+		obs[i] = float32(n)
+		i++
+	}
+	obs[i] = float32(t.Kitty)
+	i++
+	for k := 1; k < len(t.Count); k++ {
+		obs[i+k-1] = float32(t.Count[(pos+k)%len(t.Count)])
+	}
+	i += game.MaxPlayers - 1
+	if pending {
+		obs[i+int(color)] = 1
+	}
+	i += 2
+	if rules.Trump {
+		obs[i+int(t.Trump)] = 1
+	}
+	return obs
+}
+
+// A Batch is a set of Envs that are reset and stepped together, each in its
+// own goroutine.
+type Batch struct {
+	envs  []*Env
+	seeds []int64
+	obs   [][]float32
+}
+
+// NewBatch returns a Batch of n Envs played according to the given Rules,
+// each against the Players returned by a call to opponents.
+func NewBatch(n int, rules game.Rules, opponents func() []game.Player) *Batch {
+	b := &Batch{
+		envs:  make([]*Env, n),
+		seeds: make([]int64, n),
+		obs:   make([][]float32, n),
+	}
+	for i := range b.envs {
+		b.envs[i] = New(rules, opponents())
+	}
+	return b
+}
+
+// Reset resets each Env, the ith with seed+i, and returns their observations.
+// The returned slices are reused by the next call to Reset or Step.
+func (b *Batch) Reset(seed int64) [][]float32 {
+	b.each(func(i int) {
+		b.seeds[i] = seed + int64(i)
+		b.obs[i] = b.envs[i].Reset(b.seeds[i])
+	})
+	return b.obs
+}
+
+// Step steps each Env with the corresponding action, and returns their
+// observations, rewards and whether their episodes are done. An Env whose
+// episode is done is reset with its seed advanced by the number of Envs,
+// and its observation is the first of the new episode.
+// The returned obs slices are reused by the next call to Reset or Step.
+func (b *Batch) Step(actions []int) (obs [][]float32, rewards []float64, dones []bool) {
+	rewards, dones = make([]float64, len(b.envs)), make([]bool, len(b.envs))
+	b.each(func(i int) {
+		b.obs[i], rewards[i], dones[i] = b.envs[i].Step(actions[i])
+		if dones[i] {
+			b.seeds[i] += int64(len(b.envs))
+			b.obs[i] = b.envs[i].Reset(b.seeds[i])
+		}
+	})
+	return b.obs, rewards, dones
+}
+
+// each calls f with the index of each Env, concurrently.
+func (b *Batch) each(f func(i int)) {
+	var wg sync.WaitGroup
+	for i := range b.envs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
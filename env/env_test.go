@@ -0,0 +1,137 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// minor is a Player that leads from a minor suit whenever possible. Its index
+// distinguishes the opponents, since the Players of a Game must be distinct.
+type minor struct{ index int }
+
+func (minor) Init(int, int, []card.Card, []int, int) {}
+
+func (minor) Note(int, card.Card) {}
+
+func (minor) PlayMajor(game.TableState, card.Color) bool { return false }
+
+func opponents(n int) []game.Player {
+	players := make([]game.Player, n)
+	for i := range players {
+		players[i] = minor{i}
+	}
+	return players
+}
+
+// episode plays an episode with the given seed, taking actions in turn from
+// actions, and returns its observations and rewards.
+func episode(e *Env, seed int64, actions []int) (obs [][]float32, rewards []float64) {
+	obs = append(obs, append([]float32(nil), e.Reset(seed)...))
+	for i := 0; ; i++ {
+		o, r, done := e.Step(actions[i%len(actions)])
+		obs = append(obs, append([]float32(nil), o...))
+		rewards = append(rewards, r)
+		if done {
+			return obs, rewards
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	for name, rules := range map[string]game.Rules{
+		"Michigan":  game.Michigan(),
+		"Newmarket": game.Newmarket(),
+		"PopeJoan":  game.PopeJoan(),
+	} {
+		for seed := int64(0); seed < 10; seed++ {
+			obs1, rewards1 := episode(New(rules, opponents(3)), seed, []int{Major, Minor})
+			obs2, rewards2 := episode(New(rules, opponents(3)), seed, []int{Major, Minor})
+			if !reflect.DeepEqual(obs1, obs2) || !reflect.DeepEqual(rewards1, rewards2) {
+				t.Errorf("episode(%q, %v) differs between runs", name, seed)
+			}
+			for _, o := range obs1 {
+				if len(o) != Size(rules) {
+					t.Fatalf("episode(%q, %v): observation has length %v, expected %v", name, seed, len(o), Size(rules))
+				}
+			}
+		}
+	}
+}
+
+func TestObservation(t *testing.T) {
+	rules := game.Michigan()
+	e := New(rules, opponents(3))
+	obs := e.Reset(1)
+	var held, counts float32
+	for _, x := range obs[:52] {
+		held += x
+	}
+	p := len(rules.Layout)
+	for _, x := range obs[105+p : 113+p] {
+		counts += x
+	}
+	// 52 cards are dealt to four players and the widow, less those played
+	// before the agent's first decision.
+	var played float32
+	for _, x := range obs[52:104] {
+		played += x
+	}
+	if total := held + counts + played; total != 52-10 {
+		t.Errorf("observation accounts for %v cards outside the widow, expected 42", total)
+	}
+	if color := obs[113+p] + obs[114+p]; color != 1 {
+		t.Errorf("observation encodes %v decision colors, expected 1", color)
+	}
+}
+
+func TestReward(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		e := New(game.Michigan(), opponents(2))
+		e.Reset(seed)
+		// The rewards count the chips gained since the first observation,
+		// after the ante and any plays before the agent's first decision.
+		start := e.h.State().Score[e.pos]
+		var total float64
+		for done := false; !done; {
+			var r float64
+			_, r, done = e.Step(Minor)
+			total += r
+		}
+		if want := float64(e.h.State().Score[e.pos] - start); total != want {
+			t.Errorf("seed %v: total reward %v, expected the change in score %v", seed, total, want)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	rules := game.Michigan()
+	b := NewBatch(8, rules, func() []game.Player { return opponents(3) })
+	obs := b.Reset(100)
+	for i, o := range obs {
+		want := New(rules, opponents(3)).Reset(100 + int64(i))
+		if !reflect.DeepEqual(o, want) {
+			t.Errorf("Batch.Reset: observation %v differs from that of a lone Env", i)
+		}
+	}
+	actions := make([]int, 8)
+	for step := 0; step < 50; step++ {
+		_, rewards, dones := b.Step(actions)
+		if len(rewards) != 8 || len(dones) != 8 {
+			t.Fatalf("Batch.Step: %v rewards and %v dones, expected 8", len(rewards), len(dones))
+		}
+	}
+}
+
+func BenchmarkEpisode(b *testing.B) {
+	e := New(game.Michigan(), opponents(3))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.Reset(int64(i))
+		for done := false; !done; {
+			_, _, done = e.Step(Minor)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import "github.com/dkmccandless/tripoli/card"
+
+// A Policy decides in a cloned Hand from which of the given suits the player
+// at position t.Leader leads. It must return one of the suits, which must not
+// be modified, and receives a view of the public state of the hand, as a
+// Viewer does.
+type Policy func(t TableState, suits []card.Suit) card.Suit
+
+// Clone returns a copy of a Hand that may be played independently of it, for
+// exploring the consequences of a decision without changing the real game.
+// The copy holds copies of the cards, the seating and the chips of the
+// original, and like it waits for any decision that is pending.
+//
+// In the copy, the Game's Players are replaced by policy, which makes every
+// later decision, and which is also consulted among suits of mixed colors,
+// as a SuitChooser is. If policy is nil, the copy instead waits for each later
+// decision as the original does. Players are not informed of the copy's
+// events, and its Next method reports only its decisions and its EndEvent.
+func (h *Hand) Clone(policy Policy) *Hand {
+	h.g.mu.RLock()
+	defer h.g.mu.RUnlock()
+	g, r := h.g, h.r
+	cg := &Game{
+		players: make([]Player, len(g.players)),
+		score:   append([]int(nil), g.score...),
+		seats:   append([]int(nil), g.seats...),
+		kitty:   g.kitty,
+		stake:   append([]int(nil), g.stake...),
+		rules:   g.rules,
+		hands:   g.hands,
+		verify:  g.verify,
+	}
+	for pos, p := range g.players {
+		switch _, ok := p.(SuitChooser); {
+		case policy != nil:
+			cg.players[pos] = policyPlayer(policy)
+		case ok:
+			cg.players[pos] = suitWaiter{}
+		default:
+			cg.players[pos] = waiter{}
+		}
+	}
+	cr := &round{
+		g:       cg,
+		n:       append([]int(nil), r.n...),
+		deck:    append([]int(nil), r.deck...),
+		played:  append(make([]card.Card, 0, len(r.played)+len(r.deck)), r.played...),
+		run:     append(make([]card.Card, 0, len(r.deck)), r.run...),
+		leader:  r.leader,
+		widow:   append([]card.Card(nil), r.widow...),
+		start:   append([]int(nil), r.start...),
+		dealt:   append([]int(nil), r.dealt...),
+		trump:   r.trump,
+		hold:    append([]uint64(nil), r.hold...),
+		pots:    r.pots,
+		live:    r.live,
+		pending: r.pending,
+	}
+	cg.r = cr
+	c := &Hand{
+		g:        cg,
+		r:        cr,
+		number:   h.number,
+		decision: h.decision,
+		waiting:  h.waiting,
+		clone:    true,
+		auto:     policy != nil,
+	}
+	cr.steps = c
+	return c
+}
+
+// policyPlayer is a SuitChooser that makes its decisions with a Policy.
+type policyPlayer Policy
+
+func (p policyPlayer) Init(int, int, []card.Card, []int, int) {}
+
+func (p policyPlayer) Views() bool { return true }
+
+func (p policyPlayer) Note(int, card.Card) {}
+
+func (p policyPlayer) PlayMajor(t TableState, color card.Color) bool {
+	return p(t, colorSuits[color]) == color.Major()
+}
+
+func (p policyPlayer) ChooseSuit(t TableState, suits []card.Suit) card.Suit {
+	return p(t, suits)
+}
+
+// A waiter is a Player in a copy of a Hand that waits for its decisions.
+// A suitWaiter is a waiter that chooses among suits of mixed colors, as a
+// SuitChooser does.
+type waiter struct{}
+
+type suitWaiter struct{ waiter }
+
+func (waiter) Init(int, int, []card.Card, []int, int) {}
+
+func (waiter) Note(int, card.Card) {}
+
+func (waiter) PlayMajor(TableState, card.Color) bool { panic("game: waiter asked to decide") }
+
+func (suitWaiter) ChooseSuit(TableState, []card.Suit) card.Suit {
+	panic("game: waiter asked to decide")
+}
+
+// colorSuits lists the suits of each color.
+var colorSuits = [2][]card.Suit{
+	card.Black: {card.Clubs, card.Spades},
+	card.Red:   {card.Diamonds, card.Hearts},
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// noteCounter is a Player that counts the cards it is informed of.
+type noteCounter struct {
+	minor
+	notes int
+}
+
+func (nc *noteCounter) Note(int, card.Card) { nc.notes++ }
+
+// minorPolicy leads from a minor suit whenever possible, as minor does.
+func minorPolicy(t TableState, suits []card.Suit) card.Suit { return suits[0].Color().Minor() }
+
+// waiting begins hands of g until one waits for a decision, and returns it
+// with the DecisionEvent.
+func waiting(t testing.TB, g *Game) (*Hand, Event) {
+	for seed := int64(0); seed < 100; seed++ {
+		g.Seed(seed)
+		h := g.Begin()
+		for e, ok := h.Next(); ok; e, ok = h.Next() {
+			if e.Kind == DecisionEvent {
+				return h, e
+			}
+		}
+	}
+	t.Fatal("no hand waits for a decision")
+	return nil, Event{}
+}
+
+// end returns the Result reported by the EndEvent of a cloned Hand that is
+// not waiting for a decision.
+func end(t testing.TB, h *Hand) Result {
+	var last Event
+	for e, ok := h.Next(); ok; e, ok = h.Next() {
+		last = e
+	}
+	if last.Kind != EndEvent {
+		t.Fatalf("last event is %+v, expected an EndEvent", last)
+	}
+	return last.Result
+}
+
+func TestHandClone(t *testing.T) {
+	nc := &noteCounter{}
+	g := New([]Player{pa, pb, nc}, Michigan())
+	h, e := waiting(t, g)
+	state, notes := h.State(), nc.notes
+	color := e.Suits[0].Color()
+
+	minorClone, majorClone := h.Clone(minorPolicy), h.Clone(minorPolicy)
+	waitClone := h.Clone(nil)
+	if next, _ := minorClone.Next(); !reflect.DeepEqual(next, e) {
+		t.Errorf("Clone: Next is %+v, expected %+v", next, e)
+	}
+	minorClone.Decide(color.Minor())
+	majorClone.Decide(color.Major())
+	res := end(t, minorClone)
+	end(t, majorClone)
+	if c := majorClone.r.played[len(state.Played)]; c.Suit() != color.Major() {
+		t.Errorf("Clone: major branch led %v, expected a card in %v", c, color.Major())
+	}
+	if nc.notes != notes {
+		t.Errorf("Clone: Player noted %v cards, expected %v", nc.notes, notes)
+	}
+	if !reflect.DeepEqual(h.State(), state) {
+		t.Errorf("Clone: State is %+v, expected %+v", h.State(), state)
+	}
+
+	// A Clone without a Policy waits for each decision, which is made here
+	// as the original Players would make it.
+	var decisions int
+	waitClone.Decide(color.Minor())
+	for e, ok := waitClone.Next(); ok; e, ok = waitClone.Next() {
+		switch e.Kind {
+		case DecisionEvent:
+			decisions++
+			c := e.Suits[0].Color()
+			if h.Player(e.Pos).PlayMajor(waitClone.State(), c) {
+				waitClone.Decide(c.Major())
+			} else {
+				waitClone.Decide(c.Minor())
+			}
+		case EndEvent:
+			if !reflect.DeepEqual(e.Result, res) {
+				t.Errorf("Clone(nil): Result is %+v, expected %+v", e.Result, res)
+			}
+		}
+	}
+
+	h.Decide(color.Minor())
+	events := step(h)
+	if want := events[len(events)-1].Result; !reflect.DeepEqual(res, want) {
+		t.Errorf("Clone: Result is %+v, expected %+v", res, want)
+	}
+	var want int
+	for _, e := range events {
+		if e.Kind == DecisionEvent {
+			want++
+		}
+	}
+	if decisions != want {
+		t.Errorf("Clone(nil): waited for %v decisions, expected %v", decisions, want)
+	}
+}
+
+// TestCloneOutlives finishes a clone after its original Game has gone on to
+// play other hands.
+func TestCloneOutlives(t *testing.T) {
+	g := New([]Player{pa, pb, pc}, Michigan())
+	h, e := waiting(t, g)
+	kept, c := h.Clone(minorPolicy), h.Clone(minorPolicy)
+	c.Decide(e.Suits[0])
+	want := end(t, c)
+
+	h.Decide(e.Suits[0])
+	step(h)
+	g.Seed(100)
+	for i := 0; i < 3; i++ {
+		g.Play()
+	}
+
+	kept.Decide(e.Suits[0])
+	if res := end(t, kept); !reflect.DeepEqual(res, want) {
+		t.Errorf("Clone: Result is %+v, expected %+v", res, want)
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	g := New([]Player{pa, pb, pc, pd}, Michigan())
+	h, e := waiting(b, g)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := h.Clone(minorPolicy)
+		c.Decide(e.Suits[0])
+	}
+}
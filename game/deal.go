@@ -0,0 +1,78 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// A Deal specifies the cards dealt for a hand.
+type Deal struct {
+	// Hands holds each player's cards, in order of their positions in the
+	// deal, counting from the dealer's left.
+	Hands [][]card.Card
+
+	// Widow holds the cards of the extra hand. With Rules that turn up a
+	// trump, the last card of the Widow is turned up.
+	Widow []card.Card
+}
+
+// PlayDeal plays a round of Tripoli with the cards of d instead of a shuffled
+// deck. dealer is the index of the dealing Player among the Players passed to
+// New; the other Players are seated in that order, beginning at the dealer's
+// left, so that the Player following the dealer receives d.Hands[0].
+//
+// The cards of d need not be those of the Rules' Deck. PlayDeal panics if d
+// does not hold a hand for each Player or contains an invalid card, or if
+// dealer is not the index of a Player.
+func (g *Game) PlayDeal(d Deal, dealer int) {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	n := len(g.players)
+	if len(d.Hands) != n {
+		panic(fmt.Sprintf("game: Deal has %v hands for %v players", len(d.Hands), n))
+	}
+	if dealer < 0 || dealer >= n {
+		panic(fmt.Sprintf("game: dealer %v out of range [0, %v)", dealer, n))
+	}
+	for _, hand := range d.Hands {
+		checkCards(hand)
+	}
+	checkCards(d.Widow)
+
+	g.mu.Lock()
+	r := g.reset()
+	g.seat(dealer)
+	for pos, hand := range d.Hands {
+		r.hand[pos] = append(r.hand[pos], hand...)
+	}
+	r.widow = append(r.widow, d.Widow...)
+	r.setup()
+	g.mu.Unlock()
+	r.inform()
+	r.play()
+}
+
+// seat seats the Players in their order in New, beginning at the left of the
+// Player at index dealer.
+func (g *Game) seat(dealer int) {
+	n := len(g.players)
+	var players [MaxPlayers]Player
+	var score, seats [MaxPlayers]int
+	for i, s := range g.seats {
+		pos := (s - dealer - 1 + 2*n) % n
+		players[pos], score[pos], seats[pos] = g.players[i], g.score[i], s
+	}
+	copy(g.players, players[:n])
+	copy(g.score, score[:n])
+	copy(g.seats, seats[:n])
+}
+
+// checkCards panics if any of cs is not a valid Card.
+func checkCards(cs []card.Card) {
+	for _, c := range cs {
+		if c < 0 || c >= 52 {
+			panic(fmt.Sprintf("game: invalid card %d in Deal", int(c)))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+func TestPlayDeal(t *testing.T) {
+	la, lb := &listener{}, &listener{}
+	g := New([]Player{la, lb}, Michigan())
+	g.PlayDeal(Deal{
+		Hands: [][]card.Card{{12, 26}, {37, 51}},
+		Widow: []card.Card{13, 14},
+	}, 1)
+	want := []string{
+		"pass 0 [Diamonds Hearts]",
+		"kitty 0 1",
+		"collect 1 Ace 2",
+		"kitty 0 1",
+		"end 1",
+	}
+	for i, l := range []*listener{la, lb} {
+		if !reflect.DeepEqual(l.events, want) {
+			t.Errorf("Listener %v: events are %q, expected %q", i, l.events, want)
+		}
+	}
+	if score := map[Player]int{la: -7, lb: -1}; !reflect.DeepEqual(g.Score(), score) {
+		t.Errorf("Score is %v, expected %v", g.Score(), score)
+	}
+}
+
+func TestPlayDealSeats(t *testing.T) {
+	g := New([]Player{pa, pb, pc}, Michigan())
+	d := Deal{Hands: [][]card.Card{{0}, {1}, {2}}}
+	for _, test := range []struct {
+		dealer  int
+		players []Player
+	}{
+		{0, []Player{pb, pc, pa}},
+		{2, []Player{pa, pb, pc}},
+		{1, []Player{pc, pa, pb}},
+	} {
+		g.PlayDeal(d, test.dealer)
+		if !reflect.DeepEqual(g.players, test.players) {
+			t.Errorf("PlayDeal(%v): players are %v, expected %v", test.dealer, g.players, test.players)
+		}
+	}
+}
+
+func TestPlayDealCopies(t *testing.T) {
+	l := &listener{}
+	g := New([]Player{l, pb}, Michigan())
+	g.PlayDeal(Deal{Hands: [][]card.Card{{51}, {51, 50}}}, 1)
+	res := Result{
+		Winner: 0,
+		Hands:  [][]card.Card{nil, {51}},
+		Delta:  []int{-2, -4},
+	}
+	if !reflect.DeepEqual(l.res, res) {
+		t.Errorf("Result is %+v, expected %+v", l.res, res)
+	}
+}
+
+func TestPlayDealPanics(t *testing.T) {
+	for name, test := range map[string]struct {
+		d      Deal
+		dealer int
+	}{
+		"hands":  {Deal{Hands: [][]card.Card{{0}}}, 0},
+		"dealer": {Deal{Hands: [][]card.Card{{0}, {1}}}, 2},
+		"card":   {Deal{Hands: [][]card.Card{{0}, {52}}}, 0},
+		"widow":  {Deal{Hands: [][]card.Card{{0}, {1}}, Widow: []card.Card{-1}}, 0},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("PlayDeal(%q): did not panic", name)
+				}
+			}()
+			New([]Player{pa, pb}, Michigan()).PlayDeal(test.d, test.dealer)
+		}()
+	}
+}
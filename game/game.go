@@ -3,248 +3,892 @@
 package game
 
 import (
+	"errors"
+	"fmt"
+	"math/bits"
 	"math/rand"
+	"sync"
 
 	"github.com/dkmccandless/tripoli/card"
 )
 
+// MinPlayers and MaxPlayers are the smallest and largest supported numbers of
+// players in a Game.
+const (
+	MinPlayers = 2
+	MaxPlayers = 9
+)
+
+// MaxPots is the largest supported number of Pots in a Game's layout.
+const MaxPots = 64
+
 // A Game administers a game of Tripoli.
+//
+// A Game is safe for concurrent use. Hands are played one at a time: Play
+// blocks until any hand in progress on another goroutine is over. Score, Stake,
+// Kitty and State may be called from any goroutine, including from a Player's
+// methods, and return consistent snapshots. The engine locks the Game only
+// while it updates the state of a hand, and never while it calls a Player's
+// methods, so a hand that is waiting for a Player to decide does not block
+// them.
 type Game struct {
-	score map[Player]int
-	stake map[card.Card]int
+	// hand serializes the hands played by Play, and guards rng.
+	hand sync.Mutex
+
+	// mu guards the state below, and that of r, against concurrent readers.
+	// The goroutine playing a hand holds mu while it modifies the state, and
+	// reads it without locking, since no other goroutine writes to it.
+	mu sync.RWMutex
+
+	// players records the Players in order of their positions in the most
+	// recent deal, counting from the dealer's left. score records their
+	// scores, and seats their indexes in the players passed to New, in the
+	// same order.
+	players []Player
+	score   []int
+	seats   []int
+
 	kitty int
+
+	// stake records the value of each Pot of the layout.
+	stake []int
+
+	rules Rules
+
+	// rng is the source of randomness for seating and shuffling.
+	// If it is nil, the default Source of the math/rand package is used.
+	rng *rand.Rand
+
+	// hands counts the hands that have been dealt.
+	hands int
+
+	// record and verify report whether transfers of chips are recorded in
+	// ledger and verified to conserve chips. They are set only while both hand
+	// and mu are held, so a hand in progress reads them without locking.
+	record, verify bool
+	ledger         []Transfer
+
+	// r is the round in progress, or the most recent round.
+	// Its memory is reused by each subsequent round.
+	r *round
 }
 
-// New initializes a new Game.
-func New(players []Player) *Game {
-	score := make(map[Player]int)
-	for _, p := range players {
-		score[p] = 0
+// New initializes a new Game played according to the given Rules.
+// New panics if the number of players is outside the range from MinPlayers to
+// MaxPlayers, if a Player is seated more than once, if the Rules' Deck is empty
+// or holds a card outside the range 0 to 51, or if the Rules' Layout has more
+// than MaxPots Pots, Pots whose names are not unique, or invalid cards.
+func New(players []Player, rules Rules) *Game {
+	if err := validate(len(players), rules); err != nil {
+		panic(err.Error())
+	}
+	if err := distinct(players); err != nil {
+		panic(err.Error())
+	}
+	seats := make([]int, len(players))
+	for i := range seats {
+		seats[i] = i
 	}
 	return &Game{
-		score: score,
-		stake: counters(0, 0, 0, 0, 0),
+		players: append([]Player(nil), players...),
+		score:   make([]int, len(players)),
+		seats:   seats,
+		stake:   make([]int, len(rules.Layout)),
+		rules:   rules,
+	}
+}
+
+// validate returns an error describing why a Game of n players cannot be played
+// according to the given Rules, if it cannot.
+func validate(n int, rules Rules) error {
+	if n < MinPlayers || n > MaxPlayers {
+		return fmt.Errorf("game: %v players is outside the supported range [%v, %v]",
+			n, MinPlayers, MaxPlayers,
+		)
+	}
+	if len(rules.Deck) == 0 {
+		return errors.New("game: the Rules' Deck is empty")
+	}
+	for _, c := range rules.Deck {
+		if c < 0 || c >= 52 {
+			return fmt.Errorf("game: invalid card %d in the Rules' Deck", int(c))
+		}
+	}
+	if n := len(rules.Layout); n > MaxPots {
+		return fmt.Errorf("game: %v Pots exceeds the maximum of %v", n, MaxPots)
 	}
+	names := map[string]bool{LeftoverPot: true}
+	for _, p := range rules.Layout {
+		if p.Name == LeftoverPot {
+			return fmt.Errorf("game: Pot name %q is reserved", p.Name)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("game: duplicate Pot name %q", p.Name)
+		}
+		for _, c := range p.Cards {
+			if c < 0 || c >= 52 {
+				return fmt.Errorf("game: invalid card %d in Pot %q", int(c), p.Name)
+			}
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+// distinct returns an error if a Player is seated more than once. Each Player
+// must be distinct, since Score reports each Player's score by Player.
+func distinct(players []Player) error {
+	for i, p := range players {
+		for j, q := range players[:i] {
+			if p == q {
+				return fmt.Errorf("game: the Players at indexes %v and %v are the same", j, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Seed makes a Game's seating and deals reproducible by drawing them from a
+// random number generator seeded with the given value. A seeded Game does not
+// contend with other Games for the default Source of the math/rand package.
+func (g *Game) Seed(seed int64) {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	g.rng = rand.New(rand.NewSource(seed))
+}
+
+// DeckFor returns the recommended deck for a game of n players: a standard
+// deck for up to six players, and a double deck for larger tables.
+func DeckFor(n int) card.Deck {
+	if n > 6 {
+		return card.Double()
+	}
+	return card.NewDeck()
 }
 
 // Score returns the players' scores.
 func (g *Game) Score() map[Player]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	score := make(map[Player]int)
-	for p, n := range g.score {
-		score[p] = n
+	for i, p := range g.players {
+		score[p] = g.score[i]
 	}
 	return score
 }
 
-// Stake returns the values of the stake pots.
-func (g *Game) Stake() map[card.Card]int {
-	stake := make(map[card.Card]int)
-	for c, n := range g.stake {
-		stake[c] = n
+// Stake returns the values of the Pots of the layout by name.
+func (g *Game) Stake() map[string]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	stake := make(map[string]int)
+	for i, n := range g.stake {
+		stake[g.rules.Layout[i].Name] = n
 	}
 	return stake
 }
 
 // Kitty returns the value of the Kitty.
-func (g *Game) Kitty() int { return g.kitty }
+func (g *Game) Kitty() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.kitty
+}
 
 // a round administers a round of Tripoli.
 type round struct {
 	g *Game
 
-	// p records each player's position in the deal.
-	p []Player
-
 	// n records the number of cards in each player's hand.
 	n []int
 
-	// deck records the location of each card.
-	// A value of -1 indicates the extra hand/discard.
+	// deck records the location of each card. The location of the kth copy
+	// of card c is deck[52*k+c].
+	// A value of -1 indicates the extra hand/discard,
+	// or a card that is not in the Game's deck.
 	deck []int
+
+	// played records the cards played in the round, in order.
+	played []card.Card
+
+	// run records the cards played in the current run, in order.
+	run []card.Card
+
+	// leader records the position of the player who led the current run,
+	// or who must lead the next one.
+	leader int
+
+	// widow records the cards in the extra hand.
+	widow []card.Card
+
+	// start records each player's score before the ante, by position.
+	start []int
+
+	// dealt records the location of each card at the deal, as in deck.
+	dealt []int
+
+	// trump records the trump suit, if the Rules turn up trumps.
+	trump card.Suit
+
+	// hold records the cards in each player's hand as a set of bits indexed
+	// by card, by position. pots records the Pots of the layout that
+	// include each card as a set of bits indexed by Pot. They are maintained
+	// alongside deck so that lowest and collect need not search it.
+	hold []uint64
+	pots [52]uint64
+
+	// live reports whether the round is in progress, and pending records the
+	// decision for which it waits, if any.
+	live    bool
+	pending decision
+
+	// steps is the Hand that plays the round step by step, if any.
+	steps *Hand
+
+	// shuffled and hand are buffers for the shuffled deck and each player's
+	// hand, by position, which are reused from round to round.
+	shuffled card.Deck
+	hand     [][]card.Card
 }
 
-// init initializes a round with a shuffled deck, seats the players in a random
-// order, and antes for each player. init calls each Player's Init method.
+// init initializes a round with a shuffled copy of the Game's deck, seats the
+// players in a random order, and antes for each player. init calls each
+// Player's Init method.
+//
+// init reuses the memory of the Game's previous round, so that a Game plays
+// hands without allocating once it has played its first.
 func (g *Game) init() *round {
-	n := len(g.score)
-	r := &round{
-		g: g,
-		n: make([]int, n),
+	g.mu.Lock()
+	r := g.reset()
+	g.shuffle(len(g.players), func(i, j int) {
+		g.players[i], g.players[j] = g.players[j], g.players[i]
+		g.score[i], g.score[j] = g.score[j], g.score[i]
+		g.seats[i], g.seats[j] = g.seats[j], g.seats[i]
+	})
+	r.shuffled = append(r.shuffled, g.rules.Deck...)
+	r.shuffled.Shuffle(g.rng)
+	r.deal()
+	r.setup()
+	g.mu.Unlock()
+	r.inform()
+	return r
+}
+
+// reset prepares the Game's round for a new hand, reusing its memory, and
+// returns it.
+func (g *Game) reset() *round {
+	g.hands++
+	r := g.r
+	if r == nil {
+		r = &round{g: g}
+	}
+	*r = round{
+		g:        g,
+		n:        r.n[:0],
+		deck:     r.deck[:0],
+		played:   r.played[:0],
+		run:      r.run[:0],
+		widow:    r.widow[:0],
+		start:    r.start[:0],
+		dealt:    r.dealt[:0],
+		hold:     r.hold[:0],
+		shuffled: r.shuffled[:0],
+		hand:     r.hand,
+	}
+	for len(r.hand) < len(g.players) {
+		r.hand = append(r.hand, nil)
 	}
+	r.hand = r.hand[:len(g.players)]
+	for pos := range r.hand {
+		r.hand[pos] = r.hand[pos][:0]
+	}
+	g.r = r
+	return r
+}
 
-	for p := range g.score {
-		r.p = append(r.p, p)
-		r.ante(p)
+// shuffle randomizes the order of n elements using the Game's source of
+// randomness. swap swaps the elements with indexes i and j.
+func (g *Game) shuffle(n int, swap func(i, j int)) {
+	if g.rng == nil {
+		rand.Shuffle(n, swap)
+		return
 	}
-	rand.Shuffle(n, func(i, j int) { r.p[i], r.p[j] = r.p[j], r.p[i] })
+	g.rng.Shuffle(n, swap)
+}
 
-	r.deck = rand.Perm(52)
-	hand := make([][]card.Card, n)
-	for i, v := range r.deck {
-		switch v %= n + 1; {
-		case v < n:
-			// Player's hand
-			r.deck[i] = v
-			hand[v] = append(hand[v], card.Card(i))
-		case v == n:
-			// Extra hand
-			r.deck[i] = -1
+// deal deals the shuffled deck one card at a time into a hand for each player
+// and the extra hand, which is dealt last, after the dealer's hand.
+func (r *round) deal() {
+	pos := 0
+	for _, c := range r.shuffled {
+		if pos == len(r.hand) {
+			r.widow = append(r.widow, c)
+			pos = 0
+			continue
 		}
+		r.hand[pos] = append(r.hand[pos], c)
+		pos++
 	}
+}
 
-	for i, p := range r.p {
-		r.n[i] = len(hand[i])
-		stake := make(map[card.Card]int)
-		for c, v := range g.stake {
-			stake[c] = v
+// setup antes for each player and records the location of each dealt card.
+func (r *round) setup() {
+	r.start = append(r.start, r.g.score...)
+	for pos := range r.g.players {
+		r.ante(pos)
+	}
+	var count [52]int
+	k := 1
+	for _, c := range r.widow {
+		count[c]++
+	}
+	for _, hand := range r.hand {
+		for _, c := range hand {
+			count[c]++
+		}
+	}
+	for _, m := range count {
+		if m > k {
+			k = m
 		}
-		p.Init(n, i, hand[i], stake, g.kitty)
 	}
+	for len(r.deck) < 52*k {
+		r.deck = append(r.deck, -1)
+	}
+	for pos, hand := range r.hand {
+		r.n = append(r.n, len(hand))
+		for _, c := range hand {
+			i := int(c)
+			for r.deck[i] != -1 {
+				i += 52
+			}
+			r.deck[i] = pos
+		}
+	}
+	r.dealt = append(r.dealt, r.deck...)
+	if r.g.rules.Trump && len(r.widow) > 0 {
+		r.trump = r.widow[len(r.widow)-1].Suit()
+	}
+	r.index()
+	r.live = true
+}
 
-	return r
+// inform calls each Player's Init method.
+func (r *round) inform() {
+	for pos, p := range r.g.players {
+		if views(p) {
+			p.Init(len(r.n), pos, r.hand[pos], r.g.stake, r.g.kitty)
+			continue
+		}
+		hand := append([]card.Card(nil), r.hand[pos]...)
+		p.Init(len(r.n), pos, hand, append([]int(nil), r.g.stake...), r.g.kitty)
+	}
 }
 
 // Play plays a round of Tripoli.
 // The players' positions in the deal are randomly assigned.
 func (g *Game) Play() {
+	g.hand.Lock()
+	defer g.hand.Unlock()
 	g.init().play()
 }
 
+// index computes hold from deck and pots from the Rules' Layout and trump.
+func (r *round) index() {
+	for len(r.hold) < len(r.n) {
+		r.hold = append(r.hold, 0)
+	}
+	r.hold = r.hold[:len(r.n)]
+	for pos := range r.hold {
+		r.hold[pos] = 0
+	}
+	for k := 0; k < len(r.deck); k += 52 {
+		for c, pos := range r.deck[k : k+52] {
+			if pos != -1 {
+				r.hold[pos] |= 1 << uint(c)
+			}
+		}
+	}
+	r.pots = [52]uint64{}
+	for i, pot := range r.g.rules.Layout {
+		for j := range pot.Cards {
+			r.pots[pot.card(j, r.trump)] |= 1 << uint(i)
+		}
+	}
+}
+
 // play plays an initialized round of Tripoli.
 func (r *round) play() {
+	if r.g.rules.Trump && len(r.widow) > 0 {
+		// The dealer collects a Pot whose only card is turned up.
+		r.collect(len(r.n)-1, r.widow[len(r.widow)-1])
+	}
+	lead, ok := r.firstLead(r.g.rules.Start)
+	if !ok {
+		// No player was dealt a card.
+		r.end(0, false)
+		return
+	}
+	if r.g.rules.Eldest {
+		if lead, _ = r.nextLead(0, allSuits); r.pending.ok {
+			return
+		}
+	}
+	r.proceed(0, lead)
+}
+
+// proceed plays the rest of a round from a run led with a card, whose copies
+// are played in order beginning at the indicated position, until the round
+// ends or, for a round played step by step, waits for a decision.
+func (r *round) proceed(from int, lead card.Card) {
 	var pos int
 	var won bool
-	for lead := r.firstLead(); ; {
-		if pos, won = r.playRun(lead); won {
+	for {
+		if pos, won = r.playRun(from, lead); won {
 			break
 		}
 		var ok bool
-		if lead, ok = r.nextLead(pos, lead.Color().Opp()); !ok {
+		from = pos
+		if lead, ok = r.nextLead(pos, r.g.rules.Restart.suits(lead.Suit())); !ok {
+			if r.pending.ok {
+				return
+			}
 			break
 		}
 	}
-	for i := range r.p {
-		r.payKitty(r.p[i], r.n[i])
+	r.end(pos, won)
+}
+
+// end settles a round whose last run ended with a card played by the player at
+// the indicated position, who has won the round if won is true.
+func (r *round) end(pos int, won bool) {
+	for i := range r.n {
+		n := r.n[i] * r.g.rules.Leftover
+		switch {
+		case r.g.rules.PayWinner:
+			if won {
+				r.g.mu.Lock()
+				r.pay(i, pos, n)
+				r.g.mu.Unlock()
+				if n > 0 {
+					r.notify(func(l Listener) { l.Collect(pos, LeftoverPot, n) })
+					if r.steps != nil {
+						r.steps.push(Event{Kind: CollectEvent, Pos: pos, Pot: LeftoverPot, N: n})
+					}
+				}
+			}
+		default:
+			r.g.mu.Lock()
+			r.payKitty(i, n, LeftoverPenalty)
+			r.g.mu.Unlock()
+			if n > 0 {
+				r.notify(func(l Listener) { l.PayKitty(i, n) })
+				if r.steps != nil {
+					r.steps.push(Event{Kind: PayKittyEvent, Pos: i, N: n})
+				}
+			}
+		}
 	}
+	winner := -1
+	r.g.mu.Lock()
 	if won {
-		r.collectKitty(r.p[pos])
+		winner = pos
+		r.collectKitty(pos)
+	}
+	r.live = false
+	r.g.mu.Unlock()
+	if r.listening() || r.steps != nil {
+		res := r.result(winner)
+		r.notify(func(l Listener) { l.HandEnd(res) })
+		if r.steps != nil {
+			r.steps.push(Event{Kind: EndEvent, Pos: winner, Result: res})
+		}
+	}
+}
+
+// A decision records a choice of suit for which a round waits.
+type decision struct {
+	ok bool
+
+	// from is the position at which control of the lead began, and pos is
+	// the position of the deciding player.
+	from, pos int
+
+	// suits[:n] are the suits from which the player may lead.
+	suits [4]card.Suit
+	n     int
+}
+
+// allSuits lists the suits from which eldest hand may begin play.
+var allSuits = []card.Suit{card.Clubs, card.Diamonds, card.Spades, card.Hearts}
+
+// result returns the Result of a finished round.
+func (r *round) result(winner int) Result {
+	res := Result{
+		Winner: winner,
+		Hands:  make([][]card.Card, len(r.n)),
+		Widow:  append([]card.Card(nil), r.widow...),
+		Delta:  make([]int, len(r.n)),
+	}
+	for c := card.Card(0); c < 52; c++ {
+		for i := int(c); i < len(r.deck); i += 52 {
+			if pos := r.deck[i]; pos != -1 {
+				res.Hands[pos] = append(res.Hands[pos], c)
+			}
+		}
+	}
+	for i := range res.Delta {
+		res.Delta[i] = r.g.score[i] - r.start[i]
 	}
+	return res
 }
 
-// firstLead returns the lowest club held by any player.
-func (r *round) firstLead() card.Card {
-	c := card.Clubs.Rank(card.Two)
-	for r.deck[c] == -1 {
-		c++
+// firstLead returns the lowest card in a suit held by any player. If no player
+// holds a card in the suit, firstLead returns the next card held in order.
+// It reports false if no player holds any card.
+func (r *round) firstLead(s card.Suit) (card.Card, bool) {
+	c := s.Rank(card.Two)
+	for k := 0; k < 52; k, c = k+1, (c+1)%52 {
+		for i := int(c); i < len(r.deck); i += 52 {
+			if r.deck[i] != -1 {
+				return c, true
+			}
+		}
 	}
-	return c
+	return 0, false
 }
 
-// playRun plays a sequence of consecutive cards until a player is out of cards
-// or no player holds the next card. It returns the position of the player who
+// playRun plays a sequence of consecutive cards until a player is out of cards,
+// no player holds the next card, or the run stops at an ace according to the
+// Rules. It returns the position of the player who
 // played the last card and a boolean value reporting whether they have won the
 // round (by playing the last card in their hand).
-func (r *round) playRun(lead card.Card) (pos int, won bool) {
-	for c := lead; c.Suit() == lead.Suit() && r.deck[c] != -1; c++ {
-		pos = r.deck[c]
-		r.playCard(c)
-		if r.n[pos] == 0 {
-			return pos, true
+//
+// If more than one copy of a card is held, every copy is played in turn before
+// the run continues. The copies of the lead card are played in order beginning
+// at the indicated position, which must be the position of the leader or a
+// passed player to their right. The copies of each later card are played in
+// order beginning to the left of the player who played the previous card.
+func (r *round) playRun(from int, lead card.Card) (pos int, won bool) {
+	r.g.mu.Lock()
+	r.leader, _ = r.holder(lead, from)
+	r.run = r.run[:0]
+	r.g.mu.Unlock()
+	pos = from
+	for c, more := lead, true; more; c, more = r.g.rules.next(c) {
+		p, ok := r.holder(c, from)
+		if !ok {
+			break
+		}
+		for ; ok; p, ok = r.holder(c, from) {
+			pos = p
+			r.playCard(pos, c)
+			if r.n[pos] == 0 {
+				return pos, true
+			}
+			from = r.next(pos)
 		}
 	}
 	return pos, false
 }
 
-// playCard plays a card and calls each Player's Note method.
-func (r *round) playCard(c card.Card) {
-	pos := r.deck[c]
-	r.deck[c] = -1
+// holder returns the first position in order, beginning at the indicated
+// position, of a player who holds a copy of a card, and a boolean value
+// reporting whether any player holds a copy of the card.
+func (r *round) holder(c card.Card, from int) (pos int, ok bool) {
+	best := len(r.n)
+	for i := int(c); i < len(r.deck); i += 52 {
+		v := r.deck[i]
+		if v == -1 {
+			continue
+		}
+		d := v - from
+		if d < 0 {
+			d += len(r.n)
+		}
+		if d < best {
+			best, pos, ok = d, v, true
+		}
+	}
+	return pos, ok
+}
+
+// next returns the position to the left of the indicated position.
+func (r *round) next(pos int) int {
+	if pos++; pos == len(r.n) {
+		return 0
+	}
+	return pos
+}
+
+// playCard plays a copy of a card held by the player at the indicated position,
+// calls each Player's Note method, and collects any Pots that the player has
+// won by playing the card.
+func (r *round) playCard(pos int, c card.Card) {
+	r.g.mu.Lock()
+	i := int(c)
+	for r.deck[i] != pos {
+		i += 52
+	}
+	r.deck[i] = -1
+	if !r.holds(pos, c) {
+		r.hold[pos] &^= 1 << uint(c)
+	}
 	r.n[pos]--
-	r.collect(r.p[pos], c)
-	for _, p := range r.p {
+	r.played = append(r.played, c)
+	r.run = append(r.run, c)
+	r.g.mu.Unlock()
+	for _, p := range r.g.players {
 		p.Note(pos, c)
 	}
+	if r.steps != nil {
+		r.steps.push(Event{Kind: PlayEvent, Pos: pos, Card: c})
+	}
+	r.collect(pos, c)
+}
+
+// notify calls a function for each Player that implements Listener.
+func (r *round) notify(f func(Listener)) {
+	for _, p := range r.g.players {
+		if l, ok := p.(Listener); ok {
+			f(l)
+		}
+	}
+}
+
+// listening reports whether any Player implements Listener.
+func (r *round) listening() bool {
+	for _, p := range r.g.players {
+		if _, ok := p.(Listener); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // nextLead returns the lead card that begins the next run and a boolean value
 // reporting whether the game continues.
 // Control of the lead begins at the indicated position and passes as necessary
-// to the first player in order with a card in the correct color. Passed players
-// pay one point to the Kitty.
-// If the Player to lead holds cards in both suits, nextLead calls that Player's
-// PlayMajor method.
-func (r *round) nextLead(pos int, color card.Color) (lead card.Card, ok bool) {
-	// next returns the next position in order.
-	next := func(n int) int { return (n + 1) % len(r.p) }
-	for old := pos; ; pos = next(pos) {
-		minor, hasMinor := r.lowest(pos, color.Minor())
-		major, hasMajor := r.lowest(pos, color.Major())
-		switch p := r.p[pos]; {
-		case hasMinor && hasMajor:
-			if p.PlayMajor(color) {
-				return major, true
-			} else {
-				return minor, true
+// to the first player in order with a card in one of the given suits. Passed
+// players pay the Rules' Pass penalty to the Kitty.
+// nextLead calls choose to decide from which of the suits the Player leads.
+func (r *round) nextLead(pos int, suits []card.Suit) (lead card.Card, ok bool) {
+	for old := pos; ; pos = r.next(pos) {
+		r.g.mu.Lock()
+		r.leader = pos
+		r.g.mu.Unlock()
+		var buf [4]card.Suit
+		held := buf[:0]
+		for _, s := range suits {
+			if _, ok := r.lowest(pos, s); ok {
+				held = append(held, s)
 			}
-		case hasMajor:
-			return major, true
-		case hasMinor:
-			return minor, true
-		default:
-			r.payKitty(p, 1)
-			if next(pos) == old {
+		}
+		switch len(held) {
+		case 0:
+			n := r.g.rules.Pass
+			r.notify(func(l Listener) {
+				if views(l) {
+					l.Pass(pos, suits)
+				} else {
+					l.Pass(pos, append([]card.Suit(nil), suits...))
+				}
+			})
+			if r.steps != nil {
+				r.steps.push(Event{Kind: PassEvent, Pos: pos, Suits: append([]card.Suit(nil), suits...)})
+			}
+			r.g.mu.Lock()
+			r.payKitty(pos, n, PassPenalty)
+			r.g.mu.Unlock()
+			if n > 0 {
+				r.notify(func(l Listener) { l.PayKitty(pos, n) })
+				if r.steps != nil {
+					r.steps.push(Event{Kind: PayKittyEvent, Pos: pos, N: n})
+				}
+			}
+			if r.next(pos) == old {
 				return 0, false
 			}
+		default:
+			d := decision{ok: true, from: old, pos: pos}
+			d.n = copy(d.suits[:], held)
+			return r.decide(d)
+		}
+	}
+}
+
+// decide records a decision for which the round waits and calls choose to
+// make it. It returns the lowest card held by the deciding player in the
+// chosen suit and true, or, for a round played step by step, false if the
+// round must wait for the decision.
+func (r *round) decide(d decision) (card.Card, bool) {
+	r.g.mu.Lock()
+	r.pending = d
+	r.g.mu.Unlock()
+	if r.steps != nil && !r.steps.auto {
+		var buf [4]card.Suit
+		opts := r.options(d.pos, d.suits[:d.n], &buf)
+		if len(opts) > 1 {
+			r.steps.wait(Event{Kind: DecisionEvent, Pos: d.pos, Suits: append([]card.Suit(nil), opts...)})
+			return 0, false
 		}
 	}
+	s := r.choose(d.pos, d.suits[:d.n])
+	r.g.mu.Lock()
+	r.pending = decision{}
+	r.g.mu.Unlock()
+	lead, _ := r.lowest(d.pos, s)
+	return lead, true
+}
+
+// options returns the suits among which the Player at the indicated position
+// chooses, from among the suits in which they hold cards: every suit if they
+// are mixed in color and the Player implements SuitChooser, and otherwise the
+// suits of the first suit's color. options may use buf to hold its result.
+func (r *round) options(pos int, suits []card.Suit, buf *[4]card.Suit) []card.Suit {
+	color := suits[0].Color()
+	opts := buf[:0]
+	for _, s := range suits {
+		if s.Color() == color {
+			opts = append(opts, s)
+		}
+	}
+	if _, ok := r.g.players[pos].(SuitChooser); ok && len(opts) < len(suits) {
+		return suits
+	}
+	return opts
+}
+
+// choose returns the suit from which the Player at the indicated position
+// decides to lead, from among the suits in which they hold cards.
+// If the suits are the two suits of one color, choose calls the Player's
+// PlayMajor method. Otherwise, it calls the ChooseSuit method of a Player that
+// implements SuitChooser; a Player that does not chooses among the suits of
+// the first suit's color.
+func (r *round) choose(pos int, suits []card.Suit) card.Suit {
+	var buf [4]card.Suit
+	opts := r.options(pos, suits, &buf)
+	switch {
+	case len(opts) == 1:
+		return opts[0]
+	case len(opts) == 2 && opts[0].Color() == opts[1].Color():
+		color := opts[0].Color()
+		if r.g.players[pos].PlayMajor(r.snapshot(pos), color) {
+			return color.Major()
+		}
+		return color.Minor()
+	}
+	// The Player receives a copy so that suits does not escape.
+	s := r.g.players[pos].(SuitChooser).ChooseSuit(r.snapshot(pos), append([]card.Suit(nil), suits...))
+	for _, t := range suits {
+		if s == t {
+			return s
+		}
+	}
+	panic(fmt.Sprintf("game: ChooseSuit returned %v, expected one of %v",
+		s, append([]card.Suit(nil), suits...),
+	))
 }
 
 // lowest returns the lowest card held by a player in a suit,
 // and a boolean value reporting whether the player holds any cards in the suit.
 func (r *round) lowest(pos int, s card.Suit) (card.Card, bool) {
-	for c := s.Rank(card.Two); c.Suit() == s; c++ {
-		if r.deck[c] == pos {
-			return c, true
-		}
+	low := s.Rank(card.Two)
+	m := r.hold[pos] >> uint(low) & (1<<13 - 1)
+	if m == 0 {
+		return 0, false
 	}
-	return 0, false
+	return low + card.Card(bits.TrailingZeros64(m)), true
 }
 
-// ante transfers one point from a player's score to each stake pot.
-func (r *round) ante(p Player) {
-	for c := range r.g.stake {
-		r.g.score[p]--
-		r.g.stake[c]++
+// holds reports whether the player at the indicated position holds a copy of
+// a card.
+func (r *round) holds(pos int, c card.Card) bool {
+	for i := int(c); i < len(r.deck); i += 52 {
+		if r.deck[i] == pos {
+			return true
+		}
 	}
+	return false
 }
 
-// collect transfers a card's stake to a player's score.
-func (r *round) collect(p Player, c card.Card) {
-	// Do not write to stake if the key is not present
-	if r.g.stake[c] > 0 {
-		r.g.score[p] += r.g.stake[c]
-		r.g.stake[c] = 0
+// ante transfers one point from the score of the player at the indicated
+// position to each stake pot, and the Rules' KittyAnte to the kitty.
+func (r *round) ante(pos int) {
+	for i := range r.g.stake {
+		r.transfer(playerAccount(pos), potAccount(i), 1, Ante)
 	}
+	r.transfer(playerAccount(pos), kittyAccount, r.g.rules.KittyAnte, AnteKitty)
 }
 
-// payKitty transfers an amount from a player's score to the kitty.
-func (r *round) payKitty(p Player, n int) {
-	r.g.score[p] -= n
-	r.g.kitty += n
+// collect transfers to the score of the player at the indicated position the
+// stake of each Pot that includes a card and all of whose other cards the
+// player was dealt and has played, and calls the Collect method of each
+// Listener.
+func (r *round) collect(pos int, c card.Card) {
+	for m := r.pots[c]; m != 0; m &= m - 1 {
+		i := bits.TrailingZeros64(m)
+		pot := &r.g.rules.Layout[i]
+		n := r.g.stake[i]
+		if n == 0 || !r.claims(pos, pot, c) {
+			continue
+		}
+		r.g.mu.Lock()
+		r.transfer(potAccount(i), playerAccount(pos), n, CollectPot)
+		r.g.mu.Unlock()
+		r.notify(func(l Listener) { l.Collect(pos, pot.Name, n) })
+		if r.steps != nil {
+			r.steps.push(Event{Kind: CollectEvent, Pos: pos, Pot: pot.Name, N: n})
+		}
+	}
 }
 
-// collectKitty transfers the kitty to a player's score.
-func (r *round) collectKitty(p Player) {
-	r.g.score[p] += r.g.kitty
-	r.g.kitty = 0
+// claims reports whether the player at the indicated position claims a Pot by
+// playing a card.
+func (r *round) claims(pos int, pot *Pot, c card.Card) bool {
+	k := -1
+	for j := range pot.Cards {
+		if pot.card(j, r.trump) == c {
+			k = j
+			break
+		}
+	}
+	if k == -1 {
+		return false
+	}
+	for j := range pot.Cards {
+		if j != k && !r.dealtAndPlayed(pos, pot.card(j, r.trump)) {
+			return false
+		}
+	}
+	return true
 }
 
-func counters(ten, jack, queen, king, ace int) map[card.Card]int {
-	return map[card.Card]int{
-		card.Hearts.Rank(card.Ten):   ten,
-		card.Hearts.Rank(card.Jack):  jack,
-		card.Hearts.Rank(card.Queen): queen,
-		card.Hearts.Rank(card.King):  king,
-		card.Hearts.Rank(card.Ace):   ace,
+// dealtAndPlayed reports whether the player at the indicated position was
+// dealt a copy of a card that is no longer in their hand.
+func (r *round) dealtAndPlayed(pos int, c card.Card) bool {
+	for i := int(c); i < len(r.dealt); i += 52 {
+		if r.dealt[i] == pos && r.deck[i] == -1 {
+			return true
+		}
 	}
+	return false
+}
+
+// payKitty transfers a penalty from the score of the player at the indicated
+// position to the kitty.
+func (r *round) payKitty(pos, n int, reason Reason) {
+	r.transfer(playerAccount(pos), kittyAccount, n, reason)
+}
+
+// pay transfers the Leftover penalty from the score of the player at one
+// position to the score of the player at another.
+func (r *round) pay(from, to, n int) {
+	r.transfer(playerAccount(from), playerAccount(to), n, LeftoverPenalty)
+}
+
+// collectKitty transfers the kitty to the score of the player at the indicated
+// position.
+func (r *round) collectKitty(pos int) {
+	r.transfer(kittyAccount, playerAccount(pos), r.g.kitty, CollectKitty)
 }
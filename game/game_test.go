@@ -1,7 +1,9 @@
 package game
 
 import (
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/dkmccandless/tripoli/card"
@@ -12,22 +14,22 @@ import (
 // minor plays a minor suit whenever possible.
 type minor struct{ n int }
 
-func (m *minor) Init(int, int, []card.Card, map[card.Card]int, int) {}
+func (m *minor) Init(int, int, []card.Card, []int, int) {}
 
 func (m *minor) Note(int, card.Card) {}
 
-func (m *minor) PlayMajor(card.Color) bool { return false }
+func (m *minor) PlayMajor(TableState, card.Color) bool { return false }
 
 var pa, pb = &minor{0}, &minor{1}
 
 // major plays a major suit whenever possible.
 type major struct{ n int }
 
-func (m *major) Init(int, int, []card.Card, map[card.Card]int, int) {}
+func (m *major) Init(int, int, []card.Card, []int, int) {}
 
 func (m *major) Note(int, card.Card) {}
 
-func (m *major) PlayMajor(card.Color) bool { return true }
+func (m *major) PlayMajor(TableState, card.Color) bool { return true }
 
 var pc, pd = &major{0}, &major{1}
 
@@ -39,26 +41,35 @@ func TestNew(t *testing.T) {
 		{
 			[]Player{pa, pb},
 			&Game{
-				score: map[Player]int{pa: 0, pb: 0},
-				stake: counters(0, 0, 0, 0, 0),
+				players: []Player{pa, pb},
+				score:   []int{0, 0},
+				seats:   []int{0, 1},
+				stake:   counters(0, 0, 0, 0, 0),
+				rules:   Michigan(),
 			},
 		},
 		{
 			[]Player{pa, pb, pc},
 			&Game{
-				score: map[Player]int{pa: 0, pb: 0, pc: 0},
-				stake: counters(0, 0, 0, 0, 0),
+				players: []Player{pa, pb, pc},
+				score:   []int{0, 0, 0},
+				seats:   []int{0, 1, 2},
+				stake:   counters(0, 0, 0, 0, 0),
+				rules:   Michigan(),
 			},
 		},
 		{
 			[]Player{pa, pb, pc, pd},
 			&Game{
-				score: map[Player]int{pa: 0, pb: 0, pc: 0, pd: 0},
-				stake: counters(0, 0, 0, 0, 0),
+				players: []Player{pa, pb, pc, pd},
+				score:   []int{0, 0, 0, 0},
+				seats:   []int{0, 1, 2, 3},
+				stake:   counters(0, 0, 0, 0, 0),
+				rules:   Michigan(),
 			},
 		},
 	} {
-		if g := New(test.players); !reflect.DeepEqual(g, test.g) {
+		if g := New(test.players, Michigan()); !reflect.DeepEqual(g, test.g) {
 			t.Errorf("New(%v): Game is %+v, expected %+v",
 				test.players, g, test.g,
 			)
@@ -66,6 +77,55 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewPlayers(t *testing.T) {
+	for _, n := range []int{0, 1, MaxPlayers + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("New with %v players did not panic", n)
+				}
+			}()
+			New(make([]Player, n), Michigan())
+		}()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("New with a Player seated twice did not panic")
+		}
+	}()
+	New([]Player{pa, pb, pa}, Michigan())
+}
+
+func TestValidate(t *testing.T) {
+	for name, rules := range map[string]Rules{
+		"duplicate": {Deck: card.NewDeck(), Layout: []Pot{{Name: "Ace"}, {Name: "Ace"}}},
+		"reserved":  {Deck: card.NewDeck(), Layout: []Pot{{Name: LeftoverPot}}},
+		"empty":     {},
+		"deck":      {Deck: card.Deck{0, 52}},
+		"negative":  {Deck: card.Deck{-1, 0}},
+		"pot":       {Deck: card.NewDeck(), Layout: []Pot{{Name: "Ace", Cards: []card.Card{60}}}},
+	} {
+		if err := validate(4, rules); err == nil {
+			t.Errorf("validate(%v) returned no error", name)
+		}
+	}
+	if err := validate(4, Michigan()); err != nil {
+		t.Errorf("validate(Michigan()) = %v", err)
+	}
+}
+
+func TestDeckFor(t *testing.T) {
+	for n := MinPlayers; n <= MaxPlayers; n++ {
+		want := 52
+		if n > 6 {
+			want = 104
+		}
+		if d := DeckFor(n); len(d) != want {
+			t.Errorf("DeckFor(%v): got %v cards, expected %v", n, len(d), want)
+		}
+	}
+}
+
 func TestInit(t *testing.T) {
 	for _, test := range []struct {
 		players []Player
@@ -84,24 +144,24 @@ func TestInit(t *testing.T) {
 			[]int{11, 11, 10, 10},
 		},
 	} {
-		g := New(test.players)
+		g := New(test.players, Michigan())
 		r := g.init()
 		if g != r.g {
 			t.Fatalf("round %+v wraps Game %+v, expected %+v", r, r.g, g)
 		}
-		if len(r.p) != len(test.players) {
-			t.Fatalf("len(p) is %v, expected %v", len(r.p), len(test.players))
+		if len(g.players) != len(test.players) {
+			t.Fatalf("len(players) is %v, expected %v", len(g.players), len(test.players))
 		}
 		for i := range test.players {
 			var ok bool
-			for j := range r.p {
-				if test.players[i] == r.p[j] {
+			for j := range g.players {
+				if test.players[i] == g.players[j] {
 					ok = true
 					break
 				}
 			}
 			if !ok {
-				t.Errorf("player %v is not in p", i)
+				t.Errorf("player %v is not in players", i)
 			}
 		}
 		if len(r.deck) != 52 {
@@ -113,6 +173,43 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestInitDeck(t *testing.T) {
+	for name, test := range map[string]struct {
+		deck card.Deck
+		n    []int
+	}{
+		"standard": {card.NewDeck(), []int{13, 13, 13}},
+		"piquet":   {card.Stripped(card.Seven), []int{8, 8, 8}},
+		"no ace":   {card.NewDeck().Remove(card.Spades.Rank(card.Ace)), []int{13, 13, 13}},
+		"double":   {card.Double(), []int{26, 26, 26}},
+	} {
+		rules := Michigan()
+		rules.Deck = test.deck
+		r := New([]Player{pa, pb, pc}, rules).init()
+		if !reflect.DeepEqual(r.n, test.n) {
+			t.Errorf("init(%q): n is %v, expected %v", name, r.n, test.n)
+		}
+		total := len(r.widow)
+		for _, n := range r.n {
+			total += n
+		}
+		if total != len(test.deck) {
+			t.Errorf("init(%q): %v cards dealt, expected %v", name, total, len(test.deck))
+		}
+		count := make(map[card.Card]int)
+		for _, c := range test.deck {
+			count[c]++
+		}
+		for i, v := range r.deck {
+			if c := card.Card(i % 52); i/52 >= count[c] && v != -1 {
+				t.Errorf("init(%q): copy %v of card %v is not in the deck but is held by %v",
+					name, i/52, c, v,
+				)
+			}
+		}
+	}
+}
+
 func TestFirstLead(t *testing.T) {
 	for _, test := range []struct {
 		r *round
@@ -123,10 +220,17 @@ func TestFirstLead(t *testing.T) {
 		{r: &round{deck: append([]int{0, -1}, make([]int, 50)...)}, c: 0},
 		{r: &round{deck: append([]int{-1, -1}, make([]int, 50)...)}, c: 2},
 	} {
-		if c := test.r.firstLead(); c != test.c {
-			t.Errorf("firstLead(%+v): got %v, expected %v", test.r, c, test.c)
+		if c, ok := test.r.firstLead(card.Clubs); !ok || c != test.c {
+			t.Errorf("firstLead(%+v): got %v, %v, expected %v", test.r, c, ok, test.c)
 		}
 	}
+	empty := &round{deck: make([]int, 104)}
+	for i := range empty.deck {
+		empty.deck[i] = -1
+	}
+	if c, ok := empty.firstLead(card.Clubs); ok {
+		t.Errorf("firstLead with no cards held: got %v, true", c)
+	}
 }
 
 func TestAnte(t *testing.T) {
@@ -134,49 +238,55 @@ func TestAnte(t *testing.T) {
 		"2 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0},
-					stake: counters(0, 0, 0, 0, 0),
+					players: []Player{pa, pb},
+					score:   []int{0, 0},
+					stake:   counters(0, 0, 0, 0, 0),
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -5, pb: 0},
-					stake: counters(1, 1, 1, 1, 1),
+					players: []Player{pa, pb},
+					score:   []int{-5, 0},
+					stake:   counters(1, 1, 1, 1, 1),
 				},
 			},
 		},
 		"3 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 3, pb: -4, pc: -5},
-					stake: counters(0, 0, 0, 3, 3),
+					players: []Player{pa, pb, pc},
+					score:   []int{3, -4, -5},
+					stake:   counters(0, 0, 0, 3, 3),
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -2, pb: -4, pc: -5},
-					stake: counters(1, 1, 1, 4, 4),
+					players: []Player{pa, pb, pc},
+					score:   []int{-2, -4, -5},
+					stake:   counters(1, 1, 1, 4, 4),
 				},
 			},
 		},
 		"4 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -10, pb: 3, pc: -4, pd: 1},
-					stake: counters(0, 4, 0, 0, 0),
-					kitty: 6,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-10, 3, -4, 1},
+					stake:   counters(0, 4, 0, 0, 0),
+					kitty:   6,
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -15, pb: 3, pc: -4, pd: 1},
-					stake: counters(1, 5, 1, 1, 1),
-					kitty: 6,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-15, 3, -4, 1},
+					stake:   counters(1, 5, 1, 1, 1),
+					kitty:   6,
 				},
 			},
 		},
 	} {
-		if test.r.ante(pa); !reflect.DeepEqual(test.r, test.want) {
+		if test.r.ante(0); !reflect.DeepEqual(test.r, test.want) {
 			t.Errorf("ante(%q): round is %+v, expected %+v",
 				name, test.r, test.want,
 			)
@@ -191,37 +301,45 @@ func TestCollect(t *testing.T) {
 		want *round
 	}{
 		"no counter": {
-			r: &round{
+			r: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pa, pb, pc},
+					score:   []int{0, 0, 0},
+					stake:   counters(3, 3, 3, 3, 3),
+					rules:   Michigan(),
 				},
-			},
+			}),
 			c: 0,
-			want: &round{
+			want: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pa, pb, pc},
+					score:   []int{0, 0, 0},
+					stake:   counters(3, 3, 3, 3, 3),
+					rules:   Michigan(),
 				},
-			},
+			}),
 		},
 		"counter": {
-			r: &round{
+			r: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pa, pb, pc},
+					score:   []int{0, 0, 0},
+					stake:   counters(3, 3, 3, 3, 3),
+					rules:   Michigan(),
 				},
-			},
+			}),
 			c: 50,
-			want: &round{
+			want: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 3, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 0, 3),
+					players: []Player{pa, pb, pc},
+					score:   []int{3, 0, 0},
+					stake:   counters(3, 3, 3, 0, 3),
+					rules:   Michigan(),
 				},
-			},
+			}),
 		},
 	} {
-		if test.r.collect(pa, test.c); !reflect.DeepEqual(test.r, test.want) {
+		if test.r.collect(0, test.c); !reflect.DeepEqual(test.r, test.want) {
 			t.Errorf("collect(%q): round is %+v, expected %+v",
 				name, test.r, test.want,
 			)
@@ -238,49 +356,55 @@ func TestPayKitty(t *testing.T) {
 		"2 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0},
+					players: []Player{pa, pb},
+					score:   []int{0, 0},
 				},
 			},
 			1,
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 0},
-					kitty: 1,
+					players: []Player{pa, pb},
+					score:   []int{-1, 0},
+					kitty:   1,
 				},
 			},
 		},
 		"3 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 3, pb: -4, pc: -5},
-					kitty: 1,
+					players: []Player{pa, pb, pc},
+					score:   []int{3, -4, -5},
+					kitty:   1,
 				},
 			},
 			1,
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 2, pb: -4, pc: -5},
-					kitty: 2,
+					players: []Player{pa, pb, pc},
+					score:   []int{2, -4, -5},
+					kitty:   2,
 				},
 			},
 		},
 		"4 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -10, pb: 3, pc: -4, pd: 1},
-					kitty: 6,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-10, 3, -4, 1},
+					kitty:   6,
 				},
 			},
 			4,
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -14, pb: 3, pc: -4, pd: 1},
-					kitty: 10,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-14, 3, -4, 1},
+					kitty:   10,
 				},
 			},
 		},
 	} {
-		if test.r.payKitty(pa, test.n); !reflect.DeepEqual(test.r, test.want) {
+		if test.r.payKitty(0, test.n, PassPenalty); !reflect.DeepEqual(test.r, test.want) {
 			t.Errorf("payKitty(%q): round is %+v, expected %+v",
 				name, test.r, test.want,
 			)
@@ -293,47 +417,53 @@ func TestCollectKitty(t *testing.T) {
 		"2 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0},
-					kitty: 1,
+					players: []Player{pa, pb},
+					score:   []int{0, 0},
+					kitty:   1,
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 1, pb: 0},
-					kitty: 0,
+					players: []Player{pa, pb},
+					score:   []int{1, 0},
+					kitty:   0,
 				},
 			},
 		},
 		"3 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 3, pb: -4, pc: -5},
-					kitty: 1,
+					players: []Player{pa, pb, pc},
+					score:   []int{3, -4, -5},
+					kitty:   1,
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: 4, pb: -4, pc: -5},
-					kitty: 0,
+					players: []Player{pa, pb, pc},
+					score:   []int{4, -4, -5},
+					kitty:   0,
 				},
 			},
 		},
 		"4 players": {
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -10, pb: 3, pc: -4, pd: 1},
-					kitty: 6,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-10, 3, -4, 1},
+					kitty:   6,
 				},
 			},
 			&round{
 				g: &Game{
-					score: map[Player]int{pa: -4, pb: 3, pc: -4, pd: 1},
-					kitty: 0,
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-4, 3, -4, 1},
+					kitty:   0,
 				},
 			},
 		},
 	} {
-		if test.r.collectKitty(pa); !reflect.DeepEqual(test.r, test.want) {
+		if test.r.collectKitty(0); !reflect.DeepEqual(test.r, test.want) {
 			t.Errorf("collectKitty(%q): round is %+v, expected %+v",
 				name, test.r, test.want,
 			)
@@ -348,12 +478,13 @@ func TestPlayCard(t *testing.T) {
 		want *round
 	}{
 		"no counter": {
-			r: &round{
+			r: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pb, pc, pa},
+					score:   []int{0, 0, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 3, 3, 3),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{13, 13, 13},
 				deck: []int{
 					2, 0, 0, -1, -1, 0, -1, -1, 0, 0, 0, 0, 1,
@@ -361,14 +492,15 @@ func TestPlayCard(t *testing.T) {
 					0, 0, -1, 1, -1, 1, 1, 1, 1, 2, 2, -1, 1,
 					2, 2, 1, -1, 2, 2, 2, 1, -1, -1, 1, 0, 1,
 				},
-			},
+			}),
 			c: 0,
-			want: &round{
+			want: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pb, pc, pa},
+					score:   []int{0, 0, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 3, 3, 3),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{13, 13, 12},
 				deck: []int{
 					-1, 0, 0, -1, -1, 0, -1, -1, 0, 0, 0, 0, 1,
@@ -376,15 +508,18 @@ func TestPlayCard(t *testing.T) {
 					0, 0, -1, 1, -1, 1, 1, 1, 1, 2, 2, -1, 1,
 					2, 2, 1, -1, 2, 2, 2, 1, -1, -1, 1, 0, 1,
 				},
-			},
+				played: []card.Card{0},
+				run:    []card.Card{0},
+			}),
 		},
 		"counter": {
-			r: &round{
+			r: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 0, pc: 0},
-					stake: counters(3, 3, 3, 3, 3),
+					players: []Player{pb, pc, pa},
+					score:   []int{0, 0, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 3, 3, 3),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{11, 13, 12},
 				deck: []int{
 					-1, -1, -1, -1, -1, 0, -1, -1, 0, 0, 0, 0, 1,
@@ -392,14 +527,15 @@ func TestPlayCard(t *testing.T) {
 					0, 0, -1, 1, -1, 1, 1, 1, 1, 2, 2, -1, 1,
 					2, 2, 1, -1, 2, 2, 2, 1, -1, -1, 1, 0, 1,
 				},
-			},
+			}),
 			c: 50,
-			want: &round{
+			want: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 3, pc: 0},
-					stake: counters(3, 3, 3, 0, 3),
+					players: []Player{pb, pc, pa},
+					score:   []int{3, 0, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 3, 0, 3),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{10, 13, 12},
 				deck: []int{
 					-1, -1, -1, -1, -1, 0, -1, -1, 0, 0, 0, 0, 1,
@@ -407,15 +543,18 @@ func TestPlayCard(t *testing.T) {
 					0, 0, -1, 1, -1, 1, 1, 1, 1, 2, 2, -1, 1,
 					2, 2, 1, -1, 2, 2, 2, 1, -1, -1, 1, -1, 1,
 				},
-			},
+				played: []card.Card{50},
+				run:    []card.Card{50},
+			}),
 		},
 		"out": {
-			r: &round{
+			r: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 3, pc: 3},
-					stake: counters(3, 3, 3, 0, 0),
+					players: []Player{pb, pc, pa},
+					score:   []int{3, 3, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 3, 0, 0),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{8, 1, 3},
 				deck: []int{
 					-1, -1, -1, -1, -1, 0, -1, -1, 0, 0, 0, 0, -1,
@@ -423,14 +562,15 @@ func TestPlayCard(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1, -1, -1,
 				},
-			},
+			}),
 			c: 49,
-			want: &round{
+			want: indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pb: 3, pc: 6},
-					stake: counters(3, 3, 0, 0, 0),
+					players: []Player{pb, pc, pa},
+					score:   []int{3, 6, 0},
+					rules:   Michigan(),
+					stake:   counters(3, 3, 0, 0, 0),
 				},
-				p: []Player{pb, pc, pa},
 				n: []int{8, 0, 3},
 				deck: []int{
 					-1, -1, -1, -1, -1, 0, -1, -1, 0, 0, 0, 0, -1,
@@ -438,10 +578,12 @@ func TestPlayCard(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{49},
+				run:    []card.Card{49},
+			}),
 		},
 	} {
-		if test.r.playCard(test.c); !reflect.DeepEqual(test.r, test.want) {
+		if test.r.playCard(test.r.deck[test.c], test.c); !reflect.DeepEqual(test.r, test.want) {
 			t.Errorf("playCard(%q): round is %+v, expected %+v",
 				name, test.r, test.want,
 			)
@@ -458,13 +600,13 @@ func TestPlayRun(t *testing.T) {
 		want *round
 	}{
 		"extra hand": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{8, 9, 6},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -472,17 +614,17 @@ func TestPlayRun(t *testing.T) {
 					-1, 2, 2, 0, 2, 2, 2, 1, 2, 1, 1, 0, 0,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			15,
 			0,
 			false,
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{6, 8, 6},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -490,16 +632,19 @@ func TestPlayRun(t *testing.T) {
 					-1, 2, 2, 0, 2, 2, 2, 1, 2, 1, 1, 0, 0,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{15, 16, 17},
+				run:    []card.Card{15, 16, 17},
+				leader: 1,
+			}),
 		},
 		"ace": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{8, 9, 6},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -507,17 +652,17 @@ func TestPlayRun(t *testing.T) {
 					-1, 2, 2, 0, 2, 2, 2, 1, 2, 1, 1, 0, 0,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			29,
 			0,
 			false,
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{5, 6, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -525,16 +670,19 @@ func TestPlayRun(t *testing.T) {
 					-1, 2, 2, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{29, 30, 31, 32, 33, 34, 35, 36, 37, 38},
+				run:    []card.Card{29, 30, 31, 32, 33, 34, 35, 36, 37, 38},
+				leader: 0,
+			}),
 		},
 		"out": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{8, 9, 6},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -542,17 +690,17 @@ func TestPlayRun(t *testing.T) {
 					-1, 2, 2, 0, 2, 2, 2, 1, 2, 1, 1, 0, 0,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			27,
 			2,
 			true,
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -1, pb: 2, pc: -4},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 3,
+					players: []Player{pa, pb, pc},
+					score:   []int{-1, 2, -4},
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   3,
 				},
-				p: []Player{pa, pb, pc},
 				n: []int{7, 8, 0},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -560,10 +708,13 @@ func TestPlayRun(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, 1, 1, 0, 0,
 					-1, 1, 1, 1, -1, 0, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{27, 28, 29, 30, 31, 32, 33, 34},
+				run:    []card.Card{27, 28, 29, 30, 31, 32, 33, 34},
+				leader: 2,
+			}),
 		},
 	} {
-		pos, won := test.r.playRun(test.c)
+		pos, won := test.r.playRun(test.r.deck[test.c], test.c)
 		if pos != test.pos || won != test.won {
 			t.Errorf("playRun(%q): got %v, %v; expected %v, %v",
 				name, pos, won, test.pos, test.won,
@@ -577,15 +728,94 @@ func TestPlayRun(t *testing.T) {
 	}
 }
 
+// recorder records the cards played in a round in order.
+type recorder struct {
+	pos   []int
+	cards []card.Card
+}
+
+func (rec *recorder) Init(int, int, []card.Card, []int, int) {}
+
+func (rec *recorder) Note(pos int, c card.Card) {
+	rec.pos = append(rec.pos, pos)
+	rec.cards = append(rec.cards, c)
+}
+
+func (rec *recorder) PlayMajor(TableState, card.Color) bool { return false }
+
+func TestPlayRunDuplicates(t *testing.T) {
+	five, six := card.Spades.Rank(card.Five), card.Spades.Rank(card.Six)
+	deck := make([]int, 104)
+	for i := range deck {
+		deck[i] = -1
+	}
+	deck[five], deck[52+five] = 0, 2
+	deck[six], deck[52+six] = 1, 0
+	rec := &recorder{}
+	r := indexed(&round{
+		g: &Game{
+			players: []Player{rec, pa, pb},
+			score:   []int{0, 0, 0},
+			stake:   counters(0, 0, 0, 0, 0),
+		},
+		n:    []int{5, 5, 5},
+		deck: deck,
+	})
+	pos, won := r.playRun(0, five)
+	if pos != 1 || won {
+		t.Errorf("playRun: got %v, %v; expected 1, false", pos, won)
+	}
+	if want := []int{0, 2, 0, 1}; !reflect.DeepEqual(rec.pos, want) {
+		t.Errorf("playRun: positions are %v, expected %v", rec.pos, want)
+	}
+	if want := []card.Card{five, five, six, six}; !reflect.DeepEqual(rec.cards, want) {
+		t.Errorf("playRun: cards are %v, expected %v", rec.cards, want)
+	}
+	if want := []int{3, 4, 4}; !reflect.DeepEqual(r.n, want) {
+		t.Errorf("playRun: n is %v, expected %v", r.n, want)
+	}
+}
+
+func TestHolder(t *testing.T) {
+	c := card.Diamonds.Rank(card.Nine)
+	deck := make([]int, 104)
+	for i := range deck {
+		deck[i] = -1
+	}
+	deck[c], deck[52+c] = 1, 3
+	r := &round{n: make([]int, 4), deck: deck}
+	for _, test := range []struct {
+		c    card.Card
+		from int
+		pos  int
+		ok   bool
+	}{
+		{c, 0, 1, true},
+		{c, 1, 1, true},
+		{c, 2, 3, true},
+		{c, 3, 3, true},
+		{c + 1, 0, 0, false},
+	} {
+		pos, ok := r.holder(test.c, test.from)
+		if pos != test.pos || ok != test.ok {
+			t.Errorf("holder(%v, %v): got %v, %v; expected %v, %v",
+				test.c, test.from, pos, ok, test.pos, test.ok,
+			)
+		}
+	}
+}
+
 func TestLowest(t *testing.T) {
-	r := &round{
+	r := indexed(&round{
+		g: &Game{},
+		n: make([]int, 3),
 		deck: []int{
 			-1, -1, -1, -1, 0, -1, 0, -1, 1, 1, -1, 1, 1,
 			-1, -1, -1, -1, -1, -1, 2, 0, 2, -1, -1, 2, 2,
 			1, 0, -1, 2, -1, 1, -1, 2, 2, 0, -1, -1, 2,
 			-1, 1, 0, -1, 0, -1, 1, -1, 0, 0, 1, 1, 2,
 		},
-	}
+	})
 	for name, test := range map[string]struct {
 		pos int
 		s   card.Suit
@@ -616,11 +846,12 @@ func TestNextLead(t *testing.T) {
 		g     *Game
 	}{
 		"continue, force minor": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{1, 1},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -628,21 +859,24 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			0,
 			card.Red,
 			card.Diamonds.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: 0},
+				players: []Player{pa, pc},
+				score:   []int{0, 0},
+				rules:   Michigan(),
 			},
 		},
 		"continue, force major": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{1, 1},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -650,21 +884,24 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			0,
 			card.Black,
 			card.Spades.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: 0},
+				players: []Player{pa, pc},
+				score:   []int{0, 0},
+				rules:   Michigan(),
 			},
 		},
 		"continue, choose minor": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{2, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
@@ -672,21 +909,24 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 				},
-			},
+			}),
 			0,
 			card.Red,
 			card.Diamonds.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: 0},
+				players: []Player{pa, pc},
+				score:   []int{0, 0},
+				rules:   Michigan(),
 			},
 		},
 		"continue, choose major": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{2, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
@@ -694,21 +934,24 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 				},
-			},
+			}),
 			1,
 			card.Black,
 			card.Spades.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: 0},
+				players: []Player{pa, pc},
+				score:   []int{0, 0},
+				rules:   Michigan(),
 			},
 		},
 		"pass, force minor": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{1, 1},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -716,22 +959,25 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			1,
 			card.Red,
 			card.Diamonds.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: -1},
-				kitty: 1,
+				players: []Player{pa, pc},
+				score:   []int{0, -1},
+				rules:   Michigan(),
+				kitty:   1,
 			},
 		},
 		"pass, force major": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{1, 1},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -739,22 +985,25 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+			}),
 			1,
 			card.Black,
 			card.Spades.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: -1},
-				kitty: 1,
+				players: []Player{pa, pc},
+				score:   []int{0, -1},
+				rules:   Michigan(),
+				kitty:   1,
 			},
 		},
 		"pass, choose minor": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{2, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
@@ -762,22 +1011,25 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 				},
-			},
+			}),
 			1,
 			card.Red,
 			card.Diamonds.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: 0, pc: -1},
-				kitty: 1,
+				players: []Player{pa, pc},
+				score:   []int{0, -1},
+				rules:   Michigan(),
+				kitty:   1,
 			},
 		},
 		"pass, choose major": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{2, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
@@ -785,22 +1037,25 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
 				},
-			},
+			}),
 			0,
 			card.Black,
 			card.Spades.Rank(card.Ace),
 			true,
 			&Game{
-				score: map[Player]int{pa: -1, pc: 0},
-				kitty: 1,
+				players: []Player{pa, pc},
+				score:   []int{-1, 0},
+				rules:   Michigan(),
+				kitty:   1,
 			},
 		},
 		"round over": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 0, pc: 0},
+					players: []Player{pa, pc},
+					score:   []int{0, 0},
+					rules:   Michigan(),
 				},
-				p: []Player{pa, pc},
 				n: []int{1, 1},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
@@ -808,18 +1063,21 @@ func TestNextLead(t *testing.T) {
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
 				},
-			},
+			}),
 			1,
 			card.Black,
 			0,
 			false,
 			&Game{
-				score: map[Player]int{pa: -1, pc: -1},
-				kitty: 2,
+				players: []Player{pa, pc},
+				score:   []int{-1, -1},
+				rules:   Michigan(),
+				kitty:   2,
 			},
 		},
 	} {
-		lead, ok := test.r.nextLead(test.pos, test.color)
+		suits := []card.Suit{test.color.Minor(), test.color.Major()}
+		lead, ok := test.r.nextLead(test.pos, suits)
 		if lead != test.lead || ok != test.ok {
 			t.Errorf("nextLead(%q): got %v, %v; expected %v, %v",
 				name, lead, ok, test.lead, test.ok,
@@ -836,65 +1094,85 @@ func TestNextLead(t *testing.T) {
 func TestPlay(t *testing.T) {
 	for name, test := range map[string]struct{ r, want *round }{
 		"winner": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -5, pb: -5, pc: -5, pd: -5},
-					stake: counters(4, 4, 4, 4, 4),
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-5, -5, -5, -5},
+					rules:   Michigan(),
+					stake:   counters(4, 4, 4, 4, 4),
 				},
-				p: []Player{pa, pb, pc, pd},
-				n: []int{11, 11, 10, 10},
+				start: []int{0, 0, 0, 0},
+				n:     []int{11, 11, 10, 10},
 				deck: []int{
 					-1, 2, 2, 0, 3, 3, -1, 1, -1, -1, 2, 2, 3,
 					1, -1, 3, -1, 3, 0, 1, -1, 2, 1, 1, 1, 3,
 					-1, 2, 3, 0, 1, 1, -1, 1, 0, 3, 2, 2, 0,
 					3, 0, 0, 0, 3, 1, 0, 0, 1, 0, 2, 2, -1,
 				},
-			},
-			&round{
+			}),
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: 9, pb: -4, pc: 0, pd: -9},
-					stake: counters(0, 0, 0, 0, 4),
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{9, -4, 0, -9},
+					rules:   Michigan(),
+					stake:   counters(0, 0, 0, 0, 4),
 				},
-				p: []Player{pa, pb, pc, pd},
-				n: []int{0, 3, 3, 4},
+				start: []int{0, 0, 0, 0},
+				n:     []int{0, 3, 3, 4},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 2, 2, 3,
 					-1, -1, 3, -1, 3, -1, -1, -1, 2, 1, 1, 1, 3,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{
+					1, 2, 3, 4, 5, 39, 40, 41, 42, 43, 44, 45, 46,
+					47, 48, 49, 50, 27, 28, 29, 30, 31, 13, 7, 19, 33,
+					34, 35, 36, 37, 38, 18,
+				},
+				run: []card.Card{18},
+			}),
 		},
 		"no winner": {
-			&round{
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -5, pb: -5, pc: -5, pd: -5},
-					stake: counters(4, 4, 4, 4, 4),
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-5, -5, -5, -5},
+					rules:   Michigan(),
+					stake:   counters(4, 4, 4, 4, 4),
 				},
-				p: []Player{pa, pb, pc, pd},
-				n: []int{11, 11, 10, 10},
+				start: []int{0, 0, 0, 0},
+				n:     []int{11, 11, 10, 10},
 				deck: []int{
 					1, 3, 0, 2, 2, 1, 2, 2, 0, 0, 1, 0, 0,
 					2, 3, 1, 0, -1, -1, -1, 3, 0, -1, 0, -1, -1,
 					-1, 1, 1, 1, 0, 3, 1, 3, 2, 0, 3, 3, -1,
 					1, -1, 2, -1, 2, 3, 3, 3, 1, 1, 2, 0, 2,
 				},
-			},
-			&round{
+			}),
+			indexed(&round{
 				g: &Game{
-					score: map[Player]int{pa: -5, pb: 1, pc: -2, pd: -9},
-					stake: counters(0, 0, 0, 0, 0),
-					kitty: 15,
-				},
-				p: []Player{pa, pb, pc, pd},
-				n: []int{2, 1, 3, 2},
+					players: []Player{pa, pb, pc, pd},
+					score:   []int{-5, 1, -2, -9},
+					rules:   Michigan(),
+					stake:   counters(0, 0, 0, 0, 0),
+					kitty:   15,
+				},
+				start: []int{0, 0, 0, 0},
+				n:     []int{2, 1, 3, 2},
 				deck: []int{
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					2, 3, -1, -1, -1, -1, -1, 3, 0, -1, 0, -1, -1,
 					-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
 					1, -1, 2, -1, 2, -1, -1, -1, -1, -1, -1, -1, -1,
 				},
-			},
+				played: []card.Card{
+					0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12,
+					16, 30, 31, 32, 33, 34, 35, 36, 37, 44, 45, 46, 47,
+					48, 49, 50, 51, 27, 28, 29, 15,
+				},
+				run: []card.Card{15},
+			}),
 		},
 	} {
 		if test.r.play(); !reflect.DeepEqual(test.r, test.want) {
@@ -904,3 +1182,276 @@ func TestPlay(t *testing.T) {
 		}
 	}
 }
+
+// listener is a Listener that records the events it is informed of.
+type listener struct {
+	minor
+	events []string
+	res    Result
+}
+
+var _ Listener = (*listener)(nil)
+
+func (l *listener) Pass(pos int, suits []card.Suit) {
+	l.events = append(l.events, fmt.Sprintf("pass %v %v", pos, suits))
+}
+
+func (l *listener) PayKitty(pos, n int) {
+	l.events = append(l.events, fmt.Sprintf("kitty %v %v", pos, n))
+}
+
+func (l *listener) Collect(pos int, pot string, n int) {
+	l.events = append(l.events, fmt.Sprintf("collect %v %v %v", pos, pot, n))
+}
+
+func (l *listener) HandEnd(res Result) {
+	l.events = append(l.events, fmt.Sprintf("end %v", res.Winner))
+	l.res = res
+}
+
+func TestListener(t *testing.T) {
+	payWinner := Michigan()
+	payWinner.PayWinner = true
+	for name, test := range map[string]struct {
+		rules Rules
+		want  []string
+	}{
+		"Michigan": {
+			Michigan(),
+			[]string{
+				"pass 0 [Diamonds Hearts]",
+				"kitty 0 1",
+				"collect 1 Ace 2",
+				"kitty 0 1",
+				"end 1",
+			},
+		},
+		"PayWinner": {
+			payWinner,
+			[]string{
+				"pass 0 [Diamonds Hearts]",
+				"kitty 0 1",
+				"collect 1 Ace 2",
+				"collect 1 Leftover 1",
+				"end 1",
+			},
+		},
+	} {
+		la, lb := &listener{}, &listener{}
+		r := indexed(&round{
+			g: &Game{
+				players: []Player{la, lb},
+				score:   []int{-5, -5},
+				rules:   test.rules,
+				stake:   counters(2, 2, 2, 2, 2),
+			},
+			n: []int{2, 2},
+			deck: []int{
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+				0, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1, -1,
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
+			},
+			widow: []card.Card{13, 14},
+			start: []int{0, 0},
+		})
+		r.play()
+		res := Result{
+			Winner: 1,
+			Hands:  [][]card.Card{{26}, nil},
+			Widow:  []card.Card{13, 14},
+			Delta:  []int{-7, -1},
+		}
+		for i, l := range []*listener{la, lb} {
+			if !reflect.DeepEqual(l.events, test.want) {
+				t.Errorf("%v: Listener %v: events are %q, expected %q", name, i, l.events, test.want)
+			}
+			if !reflect.DeepEqual(l.res, res) {
+				t.Errorf("%v: Listener %v: Result is %+v, expected %+v", name, i, l.res, res)
+			}
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	deck := make([]int, 104)
+	for i := range deck {
+		deck[i] = -1
+	}
+	deck[3], deck[52+3], deck[40], deck[52+7] = 0, 2, 2, 0
+	r := indexed(&round{
+		g: &Game{
+			players: []Player{pb, pc, pa},
+			score:   []int{-10, 6, 4},
+		},
+		n:     []int{2, 0, 2},
+		deck:  deck,
+		widow: []card.Card{5, 5, 9},
+		start: []int{-3, 0, 3},
+	})
+	want := Result{
+		Winner: 1,
+		Hands:  [][]card.Card{{3, 7}, nil, {3, 40}},
+		Widow:  []card.Card{5, 5, 9},
+		Delta:  []int{-7, 6, 1},
+	}
+	if res := r.result(1); !reflect.DeepEqual(res, want) {
+		t.Errorf("result: got %+v, expected %+v", res, want)
+	}
+}
+
+func counters(ten, jack, queen, king, ace int) []int {
+	return []int{ten, jack, queen, king, ace}
+}
+
+func TestSeed(t *testing.T) {
+	var scores [2]map[Player]int
+	for i := range scores {
+		g := New([]Player{pa, pb, pc, pd}, Michigan())
+		g.Seed(42)
+		for j := 0; j < 10; j++ {
+			g.Play()
+		}
+		scores[i] = g.Score()
+	}
+	if !reflect.DeepEqual(scores[0], scores[1]) {
+		t.Errorf("Seed: scores %v and %v differ", scores[0], scores[1])
+	}
+}
+
+// reader is a Listener that reads the state of its Game from its methods.
+type reader struct {
+	minor
+	g *Game
+}
+
+func (r *reader) PlayMajor(TableState, card.Color) bool {
+	r.g.State()
+	return false
+}
+
+func (r *reader) Pass(int, []card.Suit) { r.g.State() }
+
+func (r *reader) PayKitty(int, int) { r.g.Score() }
+
+func (r *reader) Collect(int, string, int) { r.g.Stake() }
+
+func (r *reader) HandEnd(Result) { r.g.Kitty() }
+
+func TestConcurrentReads(t *testing.T) {
+	rd := &reader{}
+	g := New([]Player{rd, pa, pc, pd}, Michigan())
+	rd.g = g
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := g.State()
+				total := s.Kitty
+				for _, n := range s.Score {
+					total += n
+				}
+				for _, n := range s.Stake {
+					total += n
+				}
+				if total != 0 {
+					t.Errorf("State: chips total %v, expected 0: %+v", total, s)
+					return
+				}
+				g.Score()
+				g.Stake()
+				g.Kitty()
+			}
+		}()
+	}
+	var players sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		players.Add(1)
+		go func() {
+			defer players.Done()
+			for j := 0; j < 100; j++ {
+				g.Play()
+			}
+		}()
+	}
+	players.Wait()
+	close(done)
+	wg.Wait()
+}
+
+// viewing is a Viewer that plays as the Player it holds.
+type viewing struct{ Player }
+
+func (viewing) Views() bool { return true }
+
+func TestPlayAllocs(t *testing.T) {
+	for name, rules := range map[string]Rules{
+		"Michigan":  Michigan(),
+		"Newmarket": Newmarket(),
+		"PopeJoan":  PopeJoan(),
+	} {
+		g := New([]Player{viewing{pa}, viewing{pb}, viewing{pc}, viewing{pd}}, rules)
+		g.Seed(1)
+		g.Play()
+		if n := testing.AllocsPerRun(100, g.Play); n != 0 {
+			t.Errorf("Play(%q): %v allocations per hand, expected 0", name, n)
+		}
+	}
+}
+
+func BenchmarkPlay(b *testing.B) {
+	double := Michigan()
+	double.Deck = DeckFor(MaxPlayers)
+	for _, bm := range []struct {
+		name  string
+		n     int
+		rules Rules
+	}{
+		{"Michigan/2", 2, Michigan()},
+		{"Michigan/4", 4, Michigan()},
+		{"Michigan/9", 9, double},
+		{"Newmarket/4", 4, Newmarket()},
+		{"PopeJoan/4", 4, PopeJoan()},
+	} {
+		for _, view := range []bool{false, true} {
+			name := bm.name + "/Snapshot"
+			if view {
+				name = bm.name + "/View"
+			}
+			b.Run(name, func(b *testing.B) {
+				players := make([]Player, bm.n)
+				for i := range players {
+					if i%2 == 0 {
+						players[i] = &minor{i}
+					} else {
+						players[i] = &major{i}
+					}
+					if view {
+						players[i] = viewing{players[i]}
+					}
+				}
+				g := New(players, bm.rules)
+				g.Seed(1)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					g.Play()
+				}
+			})
+		}
+	}
+}
+
+// indexed computes a round's hold and pots, as init does, and returns it.
+func indexed(r *round) *round {
+	r.index()
+	return r
+}
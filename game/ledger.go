@@ -0,0 +1,184 @@
+package game
+
+import "fmt"
+
+// An Account holds chips: a player's score, a Pot of the layout, or the Kitty.
+type Account struct {
+	// Kind is the kind of the Account.
+	Kind AccountKind
+
+	// Index is the index of a player's Player among the Players passed to
+	// New, which does not change as the seating does from hand to hand, or the
+	// index of a Pot in the Rules' Layout. It is zero for the Kitty.
+	Index int
+}
+
+func (a Account) String() string {
+	switch a.Kind {
+	case PlayerAccount:
+		return fmt.Sprintf("player %d", a.Index)
+	case PotAccount:
+		return fmt.Sprintf("pot %d", a.Index)
+	default:
+		return "kitty"
+	}
+}
+
+// An AccountKind distinguishes the kinds of Accounts.
+type AccountKind int
+
+const (
+	// PlayerAccount is the Account of a player's score.
+	PlayerAccount AccountKind = iota
+
+	// PotAccount is the Account of a Pot of the layout.
+	PotAccount
+
+	// KittyAccount is the Account of the Kitty.
+	KittyAccount
+)
+
+// A Reason describes why chips are transferred.
+type Reason int
+
+//go:generate stringer -type=Reason
+const (
+	// Ante is a player's ante into a Pot of the layout.
+	Ante Reason = iota
+
+	// AnteKitty is a player's ante into the Kitty.
+	AnteKitty
+
+	// CollectPot is a player's collection of a Pot of the layout.
+	CollectPot
+
+	// PassPenalty is paid by a player who is unable to restart play.
+	PassPenalty
+
+	// LeftoverPenalty is paid at the end of a hand for the cards remaining in
+	// a player's hand.
+	LeftoverPenalty
+
+	// CollectKitty is the winner's collection of the Kitty.
+	CollectKitty
+)
+
+// A Transfer records a movement of chips from one Account to another.
+type Transfer struct {
+	// Hand is the number of the hand in which the chips were transferred,
+	// counting from 1.
+	Hand int
+
+	From, To Account
+	N        int
+	Reason   Reason
+}
+
+func (t Transfer) String() string {
+	return fmt.Sprintf("hand %d: %d from %v to %v (%v)", t.Hand, t.N, t.From, t.To, t.Reason)
+}
+
+// Record sets whether the Game records each Transfer of chips in its ledger.
+// Recording is off by default, since the ledger grows with every hand.
+func (g *Game) Record(on bool) {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.record = on
+}
+
+// Ledger returns the Transfers that the Game has recorded, in order.
+func (g *Game) Ledger() []Transfer {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]Transfer(nil), g.ledger...)
+}
+
+// Verify sets whether the Game verifies after each Transfer of chips that the
+// sum of the players' scores, the stakes and the Kitty is zero, and that no
+// stake and not the Kitty is negative. A Game in verification mode panics on
+// any violation.
+func (g *Game) Verify(on bool) {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.verify = on
+}
+
+// transfer transfers n chips from one Account to another.
+// A transfer of no chips is ignored.
+func (r *round) transfer(from, to Account, n int, reason Reason) {
+	if n == 0 {
+		return
+	}
+	*r.g.chips(from) -= n
+	*r.g.chips(to) += n
+	if !r.g.record && !r.g.verify {
+		return
+	}
+	t := Transfer{Hand: r.g.hands, From: r.g.ledgerAccount(from), To: r.g.ledgerAccount(to), N: n, Reason: reason}
+	if r.g.record {
+		r.g.ledger = append(r.g.ledger, t)
+	}
+	if r.g.verify {
+		if err := r.g.check(); err != nil {
+			panic(fmt.Sprintf("game: after %v: %v", t, err))
+		}
+	}
+}
+
+// ledgerAccount returns an Account as the ledger records it. The engine
+// identifies a player's Account by position in the hand, and the ledger by the
+// index of the Player among the Players passed to New.
+func (g *Game) ledgerAccount(a Account) Account {
+	if a.Kind == PlayerAccount {
+		a.Index = g.seats[a.Index]
+	}
+	return a
+}
+
+// chips returns a pointer to the chips held by the Account of a player at a
+// position in the hand, a Pot, or the Kitty.
+func (g *Game) chips(a Account) *int {
+	switch a.Kind {
+	case PlayerAccount:
+		return &g.score[a.Index]
+	case PotAccount:
+		return &g.stake[a.Index]
+	default:
+		return &g.kitty
+	}
+}
+
+// check returns an error describing any violation of the conservation of
+// chips.
+func (g *Game) check() error {
+	if g.kitty < 0 {
+		return fmt.Errorf("kitty is %d", g.kitty)
+	}
+	total := g.kitty
+	for i, n := range g.stake {
+		if n < 0 {
+			return fmt.Errorf("pot %d is %d", i, n)
+		}
+		total += n
+	}
+	for _, n := range g.score {
+		total += n
+	}
+	if total != 0 {
+		return fmt.Errorf("chips total %d, expected 0", total)
+	}
+	return nil
+}
+
+// playerAccount returns the Account of the player at the indicated position.
+func playerAccount(pos int) Account { return Account{Kind: PlayerAccount, Index: pos} }
+
+// potAccount returns the Account of the Pot at an index in the Layout.
+func potAccount(i int) Account { return Account{Kind: PotAccount, Index: i} }
+
+// kittyAccount is the Account of the Kitty.
+var kittyAccount = Account{Kind: KittyAccount}
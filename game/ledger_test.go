@@ -0,0 +1,121 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	for name, test := range map[string]struct {
+		from, to Account
+		n        int
+		reason   Reason
+		want     *Game
+		ledger   []Transfer
+	}{
+		"ante": {
+			playerAccount(1), potAccount(0), 1, Ante,
+			&Game{score: []int{0, -1}, stake: []int{1, 0}, hands: 1},
+			[]Transfer{{1, playerAccount(0), potAccount(0), 1, Ante}},
+		},
+		"kitty": {
+			playerAccount(0), kittyAccount, 2, PassPenalty,
+			&Game{score: []int{-2, 0}, stake: []int{0, 0}, kitty: 2, hands: 1},
+			[]Transfer{{1, playerAccount(1), kittyAccount, 2, PassPenalty}},
+		},
+		"player": {
+			playerAccount(0), playerAccount(1), 3, LeftoverPenalty,
+			&Game{score: []int{-3, 3}, stake: []int{0, 0}, hands: 1},
+			[]Transfer{{1, playerAccount(1), playerAccount(0), 3, LeftoverPenalty}},
+		},
+		"none": {
+			playerAccount(0), kittyAccount, 0, PassPenalty,
+			&Game{score: []int{0, 0}, stake: []int{0, 0}, hands: 1},
+			nil,
+		},
+	} {
+		// The players at positions 0 and 1 were passed to New in the
+		// opposite order.
+		r := &round{g: &Game{score: []int{0, 0}, seats: []int{1, 0}, stake: []int{0, 0}, hands: 1, record: true}}
+		r.transfer(test.from, test.to, test.n, test.reason)
+		test.want.seats, test.want.record, test.want.ledger = []int{1, 0}, true, test.ledger
+		if !reflect.DeepEqual(r.g, test.want) {
+			t.Errorf("transfer(%q): Game is %+v, expected %+v", name, r.g, test.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for name, test := range map[string]struct {
+		g  *Game
+		ok bool
+	}{
+		"zero":     {&Game{score: []int{0, 0}, stake: []int{0}}, true},
+		"balanced": {&Game{score: []int{-6, 1}, stake: []int{2, 1}, kitty: 2}, true},
+		"surplus":  {&Game{score: []int{-6, 2}, stake: []int{2, 1}, kitty: 2}, false},
+		"stake":    {&Game{score: []int{1, 0}, stake: []int{-1}}, false},
+		"kitty":    {&Game{score: []int{1, 0}, kitty: -1}, false},
+	} {
+		if err := test.g.check(); (err == nil) != test.ok {
+			t.Errorf("check(%q): got %v, expected ok %v", name, err, test.ok)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	r := &round{g: &Game{score: []int{1, 0}, seats: []int{0, 1}, stake: []int{0}, verify: true}}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("transfer: did not panic on a Game whose chips are not conserved")
+		}
+	}()
+	r.transfer(playerAccount(0), potAccount(0), 1, Ante)
+}
+
+func TestLedger(t *testing.T) {
+	const hands = 5
+	for name, rules := range map[string]Rules{
+		"Michigan":  Michigan(),
+		"Newmarket": Newmarket(),
+		"PopeJoan":  PopeJoan(),
+	} {
+		players := []Player{pa, pb, pc, pd}
+		g := New(players, rules)
+		g.Seed(1)
+		g.Record(true)
+		g.Verify(true)
+		for i := 0; i < hands; i++ {
+			g.Play()
+		}
+		ledger := g.Ledger()
+		if len(ledger) == 0 || ledger[0].Reason != Ante {
+			t.Fatalf("Ledger(%q): got %v, expected a ledger beginning with an Ante", name, ledger)
+		}
+		// Replay the ledger with each player's Account indexed as in
+		// players.
+		h := &Game{score: make([]int, len(players)), stake: make([]int, len(rules.Layout))}
+		prev := 1
+		for _, tr := range ledger {
+			if tr.Hand < prev || tr.Hand > hands {
+				t.Errorf("Ledger(%q): %v is out of order", name, tr)
+			}
+			prev = tr.Hand
+			*h.chips(tr.From) -= tr.N
+			*h.chips(tr.To) += tr.N
+		}
+		if prev != hands {
+			t.Errorf("Ledger(%q): last hand is %v, expected %v", name, prev, hands)
+		}
+		score := g.Score()
+		for i, p := range players {
+			if h.score[i] != score[p] {
+				t.Errorf("Ledger(%q): replay gives player %v %v chips, expected %v", name, i, h.score[i], score[p])
+			}
+		}
+		if !reflect.DeepEqual(h.stake, g.stake) || h.kitty != g.kitty {
+			t.Errorf("Ledger(%q): replay gives stakes %v and kitty %v; expected %v and %v",
+				name, h.stake, h.kitty, g.stake, g.kitty,
+			)
+		}
+	}
+}
@@ -6,15 +6,96 @@ import "github.com/dkmccandless/tripoli/card"
 type Player interface {
 	// Init informs the Player of the number of Players in the game,
 	// the Player's position in the deal (counting from the dealer's left),
-	// the Player's cards, and the values of the counter stakes and Kitty.
+	// the Player's cards, and the values of the Pots of the layout (in the
+	// order of the Rules' Layout) and Kitty.
 	// It is called once per hand, after the ante and before play begins.
-	Init(n, pos int, hand []card.Card, stake map[card.Card]int, kitty int)
+	Init(n, pos int, hand []card.Card, stake []int, kitty int)
 
 	// Note informs the Player whenever any Player plays a card.
 	Note(pos int, card card.Card)
 
 	// PlayMajor reports whether the Player decides to play from a color's
 	// "major" suit (spades or hearts) or "minor" suit (clubs or diamonds).
-	// It is only called when the Player must decide which suit to play.
-	PlayMajor(color card.Color) bool
+	// It is only called when the Player must decide which suit to play,
+	// and receives a snapshot of the public state of the hand.
+	PlayMajor(t TableState, color card.Color) bool
+}
+
+// A SuitChooser is a Player that can choose among more than two suits from
+// which to lead, as some Rules allow.
+type SuitChooser interface {
+	Player
+
+	// ChooseSuit returns the suit from which the Player decides to lead,
+	// which must be one of the given suits. It is only called when the
+	// Player holds cards in each suit and more than two suits are allowed.
+	ChooseSuit(t TableState, suits []card.Suit) card.Suit
+}
+
+// A Viewer is a Player that accepts views of the engine's state in place of
+// copies, so that the engine need not allocate them for each call, as when
+// simulating large numbers of hands. If Views reports true, the engine passes
+// the Viewer's Init method the memory in which it holds the Player's hand and
+// the stakes, its PlayMajor and ChooseSuit methods a TableState that is a view,
+// and, if it is a Listener, its Pass method the engine's list of suits. Their
+// contents must not be modified, and remain valid only until the method
+// returns.
+type Viewer interface {
+	Player
+
+	// Views reports whether the Player accepts views.
+	Views() bool
+}
+
+// views reports whether a Player accepts views of the engine's state.
+func views(p Player) bool {
+	v, ok := p.(Viewer)
+	return ok && v.Views()
+}
+
+// A Listener is a Player that is also informed of events other than card plays.
+// The engine calls these methods on every Player that implements Listener.
+type Listener interface {
+	Player
+
+	// Pass informs the Listener whenever a Player cannot lead because they
+	// hold no cards in any of the suits from which play may restart, and
+	// control of the lead passes.
+	Pass(pos int, suits []card.Suit)
+
+	// PayKitty informs the Listener whenever a Player pays n chips into the
+	// Kitty, either on passing or for cards remaining at the end of a hand.
+	PayKitty(pos, n int)
+
+	// Collect informs the Listener whenever a Player collects the n chips in
+	// a Pot of the layout. Under Rules that pay the Leftover penalty to the
+	// winner, it also informs the Listener of the chips that the winner
+	// collects from each other Player, as a Pot named LeftoverPot.
+	Collect(pos int, pot string, n int)
+
+	// HandEnd informs the Listener that the hand is over, and of its Result.
+	HandEnd(res Result)
+}
+
+// LeftoverPot is the name of the Pot reported to Listeners, and in
+// CollectEvents, when the winner collects the Leftover penalty from another
+// player. No Pot of the layout may have this name.
+const LeftoverPot = "Leftover"
+
+// A Result describes the outcome of a hand.
+type Result struct {
+	// Winner is the position of the player who played their last card and
+	// collected the Kitty, or -1 if there is none.
+	Winner int
+
+	// Hands records the cards remaining in each player's hand at the end of
+	// the hand, by position.
+	Hands [][]card.Card
+
+	// Widow records the cards in the extra hand.
+	Widow []card.Card
+
+	// Delta records the change in each player's score over the hand,
+	// including the ante, by position.
+	Delta []int
 }
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// decisions is a sequence of bytes from which Players make their decisions.
+// When it is exhausted, every decision is zero.
+type decisions []byte
+
+func (d *decisions) next() int {
+	if len(*d) == 0 {
+		return 0
+	}
+	b := (*d)[0]
+	*d = (*d)[1:]
+	return int(b)
+}
+
+// checker is a SuitChooser and Listener that makes its decisions from a
+// sequence of bytes and checks the invariants of its Game's round whenever one
+// of its methods is called.
+type checker struct {
+	t testing.TB
+	g *Game
+	d *decisions
+
+	// calls counts the calls of the checker's methods during the hand.
+	calls int
+}
+
+var _ SuitChooser = (*checker)(nil)
+var _ Listener = (*checker)(nil)
+
+func (c *checker) Init(int, int, []card.Card, []int, int) {
+	c.calls = 0
+	c.check()
+}
+
+func (c *checker) Note(int, card.Card) { c.check() }
+
+func (c *checker) PlayMajor(TableState, card.Color) bool {
+	c.check()
+	return c.d.next()%2 == 1
+}
+
+func (c *checker) ChooseSuit(_ TableState, suits []card.Suit) card.Suit {
+	c.check()
+	return suits[c.d.next()%len(suits)]
+}
+
+func (c *checker) Pass(int, []card.Suit) { c.check() }
+
+func (c *checker) PayKitty(int, int) { c.check() }
+
+func (c *checker) Collect(int, string, int) { c.check() }
+
+func (c *checker) HandEnd(res Result) {
+	c.check()
+	r := c.g.r
+	if res.Winner != -1 && (r.n[res.Winner] != 0 || len(res.Hands[res.Winner]) != 0) {
+		c.t.Fatalf("winner %v holds %v cards: %v", res.Winner, r.n[res.Winner], res.Hands[res.Winner])
+	}
+	for pos, hand := range res.Hands {
+		if len(hand) != r.n[pos] {
+			c.t.Fatalf("Result holds %v cards for position %v, expected %v", len(hand), pos, r.n[pos])
+		}
+	}
+}
+
+// check checks the invariants of the round in progress.
+func (c *checker) check() {
+	r := c.g.r
+	c.calls++
+	if limit := 4 * len(r.deck) * len(r.n); c.calls > limit {
+		c.t.Fatalf("hand has not ended after %v calls", limit)
+	}
+	n := make([]int, len(r.n))
+	hold := make([]uint64, len(r.n))
+	for i, pos := range r.deck {
+		if pos != -1 {
+			n[pos]++
+			hold[pos] |= 1 << uint(i%52)
+		}
+	}
+	for pos := range n {
+		if n[pos] != r.n[pos] {
+			c.t.Fatalf("position %v holds %v cards, but n is %v", pos, n[pos], r.n)
+		}
+		if hold[pos] != r.hold[pos] {
+			c.t.Fatalf("position %v holds %x, but hold is %x", pos, hold[pos], r.hold[pos])
+		}
+	}
+	var played, held, dealt [52]int
+	for _, d := range r.played {
+		played[d]++
+	}
+	for i := range r.deck {
+		if r.deck[i] != -1 {
+			held[i%52]++
+		}
+		if r.dealt[i] != -1 {
+			dealt[i%52]++
+		}
+	}
+	for d := range dealt {
+		if played[d]+held[d] != dealt[d] {
+			c.t.Fatalf("%v: %v copies played and %v held, but %v dealt",
+				card.Card(d), played[d], held[d], dealt[d],
+			)
+		}
+	}
+	if err := c.g.check(); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// playChecked plays a hand with a seeded deal among n checkers, which make
+// their decisions from d, according to one of several variants of the Rules.
+func playChecked(t testing.TB, seed int64, n, variant int, d decisions) {
+	rules := []func() Rules{Michigan, Newmarket, PopeJoan}[variant%3]()
+	if variant%3 != 2 {
+		rules.Deck = DeckFor(n)
+	}
+	rules.Restart = Restart(variant / 3 % 4)
+	rules.Wrap = variant/12%2 == 1
+	players := make([]Player, n)
+	checkers := make([]*checker, n)
+	for i := range players {
+		checkers[i] = &checker{t: t, d: &d}
+		players[i] = checkers[i]
+	}
+	g := New(players, rules)
+	for _, c := range checkers {
+		c.g = g
+	}
+	g.Seed(seed)
+	g.Verify(true)
+	g.init().play()
+}
+
+func TestPlayProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for seed := int64(0); seed < 500; seed++ {
+		d := make(decisions, 64)
+		rng.Read(d)
+		n := MinPlayers + rng.Intn(MaxPlayers-MinPlayers+1)
+		playChecked(t, seed, n, rng.Intn(24), d)
+	}
+}
+
+func FuzzPlay(f *testing.F) {
+	f.Add(int64(0), uint8(4), uint8(0), []byte{})
+	f.Add(int64(1), uint8(2), uint8(13), []byte{1, 2, 3})
+	f.Add(int64(2), uint8(9), uint8(5), []byte{0xff, 0, 0xff})
+	f.Add(int64(3), uint8(3), uint8(8), []byte{7, 7, 7, 7})
+	f.Fuzz(func(t *testing.T, seed int64, n, variant uint8, d []byte) {
+		players := MinPlayers + int(n)%(MaxPlayers-MinPlayers+1)
+		playChecked(t, seed, players, int(variant), d)
+	})
+}
@@ -0,0 +1,259 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// Rules describe the variant of the game played by a Game.
+type Rules struct {
+	// Deck is the deck that is shuffled and dealt for each hand.
+	// It must not be empty, and may contain more than one copy of each card.
+	Deck card.Deck
+
+	// Layout lists the Pots of the layout. It may include at most MaxPots
+	// Pots, and each Pot's Name must be unique and not LeftoverPot.
+	Layout []Pot
+
+	// Trump reports whether the last card of the extra hand is turned up to
+	// determine the trump suit for the hand. If the turned card is the only
+	// card of a Pot, the dealer collects the Pot.
+	Trump bool
+
+	// KittyAnte is the number of chips each player antes into the Kitty
+	// before each hand.
+	KittyAnte int
+
+	// Start is the suit whose lowest card held by any player begins play.
+	Start card.Suit
+
+	// Eldest reports whether play begins instead with the player to the
+	// dealer's left, who leads the lowest card of a suit of their choice.
+	Eldest bool
+
+	// Restart determines the suits from which play restarts after a run
+	// stops.
+	Restart Restart
+
+	// Pass is the number of chips a player pays to the Kitty when they are
+	// unable to restart play.
+	Pass int
+
+	// Leftover is the number of chips a player pays to the Kitty at the end
+	// of a hand for each card remaining in their hand.
+	Leftover int
+
+	// PayWinner reports whether the Leftover penalty is paid directly to the
+	// winner instead of to the Kitty. If there is no winner, it is not paid.
+	PayWinner bool
+
+	// Wrap reports whether a run continues past the ace into the two of the
+	// same suit. If Wrap is false, an ace stops the run.
+	Wrap bool
+}
+
+// Michigan returns the default Rules, which are described in the package
+// documentation. It is played with a standard deck.
+func Michigan() Rules {
+	return Rules{
+		Deck: card.NewDeck(),
+		Layout: []Pot{
+			{Name: "Ten", Cards: []card.Card{card.Hearts.Rank(card.Ten)}},
+			{Name: "Jack", Cards: []card.Card{card.Hearts.Rank(card.Jack)}},
+			{Name: "Queen", Cards: []card.Card{card.Hearts.Rank(card.Queen)}},
+			{Name: "King", Cards: []card.Card{card.Hearts.Rank(card.King)}},
+			{Name: "Ace", Cards: []card.Card{card.Hearts.Rank(card.Ace)}},
+		},
+		Start:    card.Clubs,
+		Restart:  OppositeColor,
+		Pass:     1,
+		Leftover: 1,
+	}
+}
+
+// Newmarket returns the Rules of Newmarket, also known as Boodle.
+// The boodle cards of the layout are the ace of hearts, king of clubs, queen
+// of diamonds and jack of spades, and each player also antes one chip into the
+// Kitty. The player to the dealer's left begins play in any suit, and play
+// restarts in any suit other than that of the run that stopped. Passing costs
+// nothing. The winner collects the Kitty and one chip from each other player
+// for each card remaining in their hand.
+func Newmarket() Rules {
+	return Rules{
+		Deck: card.NewDeck(),
+		Layout: []Pot{
+			{Name: "Ace", Cards: []card.Card{card.Hearts.Rank(card.Ace)}},
+			{Name: "King", Cards: []card.Card{card.Clubs.Rank(card.King)}},
+			{Name: "Queen", Cards: []card.Card{card.Diamonds.Rank(card.Queen)}},
+			{Name: "Jack", Cards: []card.Card{card.Spades.Rank(card.Jack)}},
+		},
+		KittyAnte: 1,
+		Eldest:    true,
+		Restart:   OtherSuit,
+		Leftover:  1,
+		PayWinner: true,
+	}
+}
+
+// PopeJoan returns the Rules of Pope Joan, which is played with a deck
+// stripped of the eight of diamonds. The last card of the extra hand is turned
+// up for trumps. The layout holds the Pope (the nine of diamonds); Matrimony
+// (the king and queen of trumps) and Intrigue (the queen and jack of trumps),
+// which are collected by a player who plays both cards; and the ace, king,
+// queen and jack of trumps. The Kitty serves as the Game pot: each player
+// antes one chip into it, and the winner collects it and one chip from each
+// other player for each card remaining in their hand.
+// As in the other Rules, a lead is the lowest card of the chosen suit.
+// The player to the dealer's left begins play, and play restarts in any suit.
+func PopeJoan() Rules {
+	honor := func(name string, ranks ...card.Rank) Pot {
+		p := Pot{Name: name, Trump: true}
+		for _, r := range ranks {
+			p.Cards = append(p.Cards, card.Hearts.Rank(r))
+		}
+		return p
+	}
+	return Rules{
+		Deck: card.NewDeck().Remove(card.Diamonds.Rank(card.Eight)),
+		Layout: []Pot{
+			{Name: "Pope", Cards: []card.Card{card.Diamonds.Rank(card.Nine)}},
+			honor("Matrimony", card.King, card.Queen),
+			honor("Intrigue", card.Queen, card.Jack),
+			honor("Ace", card.Ace),
+			honor("King", card.King),
+			honor("Queen", card.Queen),
+			honor("Jack", card.Jack),
+		},
+		Trump:     true,
+		KittyAnte: 1,
+		Eldest:    true,
+		Restart:   AnySuit,
+		Leftover:  1,
+		PayWinner: true,
+	}
+}
+
+// named records the Rules returned by RulesNamed by name, and whether each is
+// played with a standard deck, which RulesNamed replaces with the deck that
+// DeckFor recommends.
+var named = map[string]struct {
+	rules    func() Rules
+	standard bool
+}{
+	"michigan":  {Michigan, true},
+	"newmarket": {Newmarket, true},
+	"popejoan":  {PopeJoan, false},
+}
+
+// RulesNamed returns the Rules of a name, in any case: "michigan",
+// "newmarket" or "popejoan", for a game of n players. Rules played with a
+// standard deck are given the deck that DeckFor recommends for n players,
+// and PopeJoan keeps its stripped deck. RulesNamed returns an error if the
+// name is unknown or if n players are not supported.
+func RulesNamed(name string, n int) (Rules, error) {
+	r, ok := named[strings.ToLower(name)]
+	if !ok {
+		return Rules{}, fmt.Errorf("game: unknown Rules %q", name)
+	}
+	rules := r.rules()
+	if r.standard {
+		rules.Deck = DeckFor(n)
+	}
+	if err := validate(n, rules); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// A Pot is a stake on the layout. Before each hand, each player antes one chip
+// into each Pot. A player who plays all of a Pot's Cards in a hand collects it.
+type Pot struct {
+	// Name identifies the Pot.
+	Name string
+
+	// Cards are the cards that a player must play to collect the Pot.
+	Cards []card.Card
+
+	// Trump reports whether the Cards are given as hearts and stand for the
+	// cards of the same ranks in the trump suit.
+	Trump bool
+}
+
+// card returns the ith of a Pot's Cards when trumps are the given suit.
+func (p Pot) card(i int, trump card.Suit) card.Card {
+	if p.Trump {
+		return trump.Rank(p.Cards[i].Rank())
+	}
+	return p.Cards[i]
+}
+
+// A Restart determines the suits from which play may restart after a run
+// stops.
+type Restart int
+
+const (
+	// OppositeColor restarts play in either suit of the opposite color.
+	OppositeColor Restart = iota
+
+	// SameColor restarts play in either suit of the same color.
+	SameColor
+
+	// AnySuit restarts play in any suit.
+	AnySuit
+
+	// OtherSuit restarts play in any suit other than that of the run that
+	// stopped.
+	OtherSuit
+)
+
+// suits returns the suits from which play may restart after a run in suit s
+// stops. The suits of the preferred color are listed first, minor suit first.
+// The returned slice is shared and must not be modified.
+func (rs Restart) suits(s card.Suit) []card.Suit {
+	if rs < OppositeColor || rs > OtherSuit {
+		rs = OppositeColor
+	}
+	return restarts[rs][s]
+}
+
+// restarts records the suits returned by Restart.suits, by Restart and suit,
+// so that they are not allocated anew for each run.
+var restarts = func() (t [OtherSuit + 1][4][]card.Suit) {
+	for rs := range t {
+		for s := range t[rs] {
+			t[rs][s] = Restart(rs).order(card.Suit(s))
+		}
+	}
+	return t
+}()
+
+// order lists the suits from which play may restart after a run in suit s
+// stops, as described in suits.
+func (rs Restart) order(s card.Suit) []card.Suit {
+	opp, same := s.Color().Opp(), s.Color()
+	switch rs {
+	case SameColor:
+		return []card.Suit{same.Minor(), same.Major()}
+	case AnySuit:
+		return []card.Suit{opp.Minor(), opp.Major(), same.Minor(), same.Major()}
+	case OtherSuit:
+		suits := []card.Suit{opp.Minor(), opp.Major()}
+		if s == same.Minor() {
+			return append(suits, same.Major())
+		}
+		return append(suits, same.Minor())
+	default:
+		return []card.Suit{opp.Minor(), opp.Major()}
+	}
+}
+
+// next returns the card that follows c in a run, and a boolean value reporting
+// whether the run may continue past c.
+func (rules Rules) next(c card.Card) (card.Card, bool) {
+	if c.Rank() != card.Ace {
+		return c + 1, true
+	}
+	return c.Suit().Rank(card.Two), rules.Wrap
+}
@@ -0,0 +1,391 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+func TestRestartSuits(t *testing.T) {
+	for _, test := range []struct {
+		rs    Restart
+		s     card.Suit
+		suits []card.Suit
+	}{
+		{OppositeColor, card.Clubs, []card.Suit{card.Diamonds, card.Hearts}},
+		{OppositeColor, card.Hearts, []card.Suit{card.Clubs, card.Spades}},
+		{SameColor, card.Clubs, []card.Suit{card.Clubs, card.Spades}},
+		{SameColor, card.Diamonds, []card.Suit{card.Diamonds, card.Hearts}},
+		{AnySuit, card.Spades, []card.Suit{card.Diamonds, card.Hearts, card.Clubs, card.Spades}},
+		{AnySuit, card.Diamonds, []card.Suit{card.Clubs, card.Spades, card.Diamonds, card.Hearts}},
+		{OtherSuit, card.Clubs, []card.Suit{card.Diamonds, card.Hearts, card.Spades}},
+		{OtherSuit, card.Hearts, []card.Suit{card.Clubs, card.Spades, card.Diamonds}},
+	} {
+		if suits := test.rs.suits(test.s); !reflect.DeepEqual(suits, test.suits) {
+			t.Errorf("Restart(%v).suits(%v): got %v, expected %v",
+				test.rs, test.s, suits, test.suits,
+			)
+		}
+	}
+}
+
+func TestRulesNext(t *testing.T) {
+	for _, test := range []struct {
+		wrap bool
+		c    card.Card
+		next card.Card
+		ok   bool
+	}{
+		{false, card.Spades.Rank(card.Two), card.Spades.Rank(card.Three), true},
+		{false, card.Spades.Rank(card.King), card.Spades.Rank(card.Ace), true},
+		{false, card.Spades.Rank(card.Ace), card.Spades.Rank(card.Two), false},
+		{true, card.Spades.Rank(card.Ace), card.Spades.Rank(card.Two), true},
+		{true, card.Hearts.Rank(card.Ace), card.Hearts.Rank(card.Two), true},
+	} {
+		next, ok := Rules{Wrap: test.wrap}.next(test.c)
+		if next != test.next || ok != test.ok {
+			t.Errorf("Rules{Wrap: %v}.next(%v): got %v, %v; expected %v, %v",
+				test.wrap, test.c, next, ok, test.next, test.ok,
+			)
+		}
+	}
+}
+
+func TestFirstLeadStart(t *testing.T) {
+	deck := make([]int, 52)
+	for i := range deck {
+		deck[i] = -1
+	}
+	deck[card.Clubs.Rank(card.Five)] = 0
+	deck[card.Diamonds.Rank(card.Nine)] = 1
+	deck[card.Hearts.Rank(card.Jack)] = 1
+	r := &round{deck: deck}
+	for _, test := range []struct {
+		s card.Suit
+		c card.Card
+	}{
+		{card.Clubs, card.Clubs.Rank(card.Five)},
+		{card.Diamonds, card.Diamonds.Rank(card.Nine)},
+		{card.Spades, card.Hearts.Rank(card.Jack)},
+		{card.Hearts, card.Hearts.Rank(card.Jack)},
+	} {
+		if c, _ := r.firstLead(test.s); c != test.c {
+			t.Errorf("firstLead(%v): got %v, expected %v", test.s, c, test.c)
+		}
+	}
+}
+
+func TestPlayRunWrap(t *testing.T) {
+	for name, test := range map[string]struct {
+		wrap bool
+		pos  int
+		n    []int
+	}{
+		"stop": {false, 1, []int{3, 2}},
+		"wrap": {true, 0, []int{1, 2}},
+	} {
+		deck := make([]int, 52)
+		for i := range deck {
+			deck[i] = -1
+		}
+		deck[card.Spades.Rank(card.King)] = 0
+		deck[card.Spades.Rank(card.Ace)] = 1
+		deck[card.Spades.Rank(card.Two)] = 0
+		deck[card.Spades.Rank(card.Three)] = 0
+		deck[card.Spades.Rank(card.Five)] = 1
+		deck[card.Hearts.Rank(card.Two)] = 0
+		deck[card.Hearts.Rank(card.Three)] = 1
+		r := indexed(&round{
+			g: &Game{
+				players: []Player{pa, pb},
+				score:   []int{0, 0},
+				rules:   Rules{Wrap: test.wrap},
+			},
+			n:    []int{4, 3},
+			deck: deck,
+		})
+		pos, won := r.playRun(0, card.Spades.Rank(card.King))
+		if pos != test.pos || won {
+			t.Errorf("playRun(%q): got %v, %v; expected %v, false",
+				name, pos, won, test.pos,
+			)
+		}
+		if !reflect.DeepEqual(r.n, test.n) {
+			t.Errorf("playRun(%q): n is %v, expected %v", name, r.n, test.n)
+		}
+	}
+}
+
+// chooser is a SuitChooser that always chooses the last suit offered.
+type chooser struct{ minor }
+
+func (c *chooser) ChooseSuit(_ TableState, suits []card.Suit) card.Suit {
+	return suits[len(suits)-1]
+}
+
+func TestChoose(t *testing.T) {
+	ch := &chooser{}
+	for name, test := range map[string]struct {
+		p     Player
+		suits []card.Suit
+		s     card.Suit
+	}{
+		"one suit":            {pa, []card.Suit{card.Spades}, card.Spades},
+		"minor player":        {pa, []card.Suit{card.Clubs, card.Spades}, card.Clubs},
+		"major player":        {pc, []card.Suit{card.Diamonds, card.Hearts}, card.Hearts},
+		"chooser, one color":  {ch, []card.Suit{card.Clubs, card.Spades}, card.Clubs},
+		"chooser":             {ch, []card.Suit{card.Diamonds, card.Clubs, card.Spades}, card.Spades},
+		"fallback, minor":     {pa, []card.Suit{card.Diamonds, card.Hearts, card.Clubs}, card.Diamonds},
+		"fallback, major":     {pc, []card.Suit{card.Diamonds, card.Hearts, card.Clubs}, card.Hearts},
+		"fallback, one color": {pc, []card.Suit{card.Hearts, card.Clubs, card.Spades}, card.Hearts},
+	} {
+		r := &round{
+			g: &Game{players: []Player{test.p}, score: []int{0}},
+			n: []int{3},
+		}
+		if s := r.choose(0, test.suits); s != test.s {
+			t.Errorf("choose(%q): got %v, expected %v", name, s, test.s)
+		}
+	}
+}
+
+func TestPlayRules(t *testing.T) {
+	for name, test := range map[string]struct {
+		pass, leftover int
+		score          []int
+		kitty          int
+	}{
+		"default": {1, 1, []int{-7, -1}, 0},
+		"no pass": {0, 1, []int{-6, -2}, 0},
+		"heavy":   {2, 3, []int{-10, 2}, 0},
+	} {
+		rules := Michigan()
+		rules.Pass, rules.Leftover = test.pass, test.leftover
+		r := indexed(&round{
+			g: &Game{
+				players: []Player{pa, pb},
+				score:   []int{-5, -5},
+				stake:   counters(2, 2, 2, 2, 2),
+				rules:   rules,
+			},
+			n: []int{2, 2},
+			deck: []int{
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+				0, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1, -1,
+				-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
+			},
+			start: []int{0, 0},
+		})
+		r.play()
+		if !reflect.DeepEqual(r.g.score, test.score) || r.g.kitty != test.kitty {
+			t.Errorf("play(%q): score and kitty are %v, %v; expected %v, %v",
+				name, r.g.score, r.g.kitty, test.score, test.kitty,
+			)
+		}
+	}
+}
+
+func TestNewmarketInit(t *testing.T) {
+	g := New([]Player{pa, pb}, Newmarket())
+	g.init()
+	want := map[string]int{"Ace": 2, "King": 2, "Queen": 2, "Jack": 2}
+	if !reflect.DeepEqual(g.Stake(), want) {
+		t.Errorf("stake is %v, expected %v", g.stake, want)
+	}
+	if g.kitty != 2 {
+		t.Errorf("kitty is %v, expected 2", g.kitty)
+	}
+	if score := []int{-5, -5}; !reflect.DeepEqual(g.score, score) {
+		t.Errorf("score is %v, expected %v", g.score, score)
+	}
+}
+
+func TestNewmarketPlay(t *testing.T) {
+	la, lb := &listener{}, &listener{}
+	rules := Newmarket()
+	stake := []int{2, 2, 2, 2}
+	r := indexed(&round{
+		g: &Game{
+			players: []Player{la, lb},
+			score:   []int{-5, -5},
+			stake:   stake,
+			kitty:   2,
+			rules:   rules,
+		},
+		n: []int{2, 2},
+		deck: []int{
+			-1, -1, -1, 0, 1, -1, -1, -1, -1, -1, -1, 1, -1,
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+			-1, -1, -1, -1, -1, -1, -1, 0, -1, -1, -1, -1, -1,
+		},
+		start: []int{0, 0},
+	})
+	r.play()
+	if want := []card.Card{3, 4, 46}; !reflect.DeepEqual(r.played, want) {
+		t.Errorf("played %v, expected %v", r.played, want)
+	}
+	if score := []int{-2, -6}; !reflect.DeepEqual(r.g.score, score) {
+		t.Errorf("score is %v, expected %v", r.g.score, score)
+	}
+	if r.g.kitty != 0 {
+		t.Errorf("kitty is %v, expected 0", r.g.kitty)
+	}
+	want := []string{
+		"pass 1 [Diamonds Hearts Spades]",
+		"collect 0 Leftover 1",
+		"end 0",
+	}
+	if !reflect.DeepEqual(la.events, want) {
+		t.Errorf("events are %q, expected %q", la.events, want)
+	}
+}
+
+func TestPopeJoanDeck(t *testing.T) {
+	d := PopeJoan().Deck
+	if len(d) != 51 {
+		t.Errorf("len(Deck) is %v, expected 51", len(d))
+	}
+	for _, c := range d {
+		if c == card.Diamonds.Rank(card.Eight) {
+			t.Errorf("Deck contains the eight of diamonds")
+		}
+	}
+}
+
+func TestRulesNamed(t *testing.T) {
+	for name, test := range map[string]struct {
+		n    int
+		deck card.Deck
+	}{
+		"Michigan":  {4, card.NewDeck()},
+		"michigan":  {8, card.Double()},
+		"newmarket": {7, card.Double()},
+		"popejoan":  {8, PopeJoan().Deck},
+	} {
+		r, err := RulesNamed(name, test.n)
+		if err != nil {
+			t.Errorf("RulesNamed(%q, %v): %v", name, test.n, err)
+		} else if !reflect.DeepEqual(r.Deck, test.deck) {
+			t.Errorf("RulesNamed(%q, %v): Deck is %v, expected %v", name, test.n, r.Deck, test.deck)
+		}
+	}
+	for name, n := range map[string]int{"whist": 4, "michigan": 1} {
+		if _, err := RulesNamed(name, n); err == nil {
+			t.Errorf("RulesNamed(%q, %v): got no error", name, n)
+		}
+	}
+}
+
+func TestPotCard(t *testing.T) {
+	for _, test := range []struct {
+		pot   Pot
+		i     int
+		trump card.Suit
+		c     card.Card
+	}{
+		{Pot{Cards: []card.Card{card.Diamonds.Rank(card.Nine)}}, 0, card.Spades, card.Diamonds.Rank(card.Nine)},
+		{Pot{Cards: []card.Card{card.Hearts.Rank(card.Ace)}, Trump: true}, 0, card.Clubs, card.Clubs.Rank(card.Ace)},
+		{Pot{Cards: []card.Card{card.Hearts.Rank(card.King), card.Hearts.Rank(card.Queen)}, Trump: true}, 1, card.Diamonds, card.Diamonds.Rank(card.Queen)},
+	} {
+		if c := test.pot.card(test.i, test.trump); c != test.c {
+			t.Errorf("%+v.card(%v, %v): got %v, expected %v",
+				test.pot, test.i, test.trump, c, test.c,
+			)
+		}
+	}
+}
+
+func TestPopeJoanInit(t *testing.T) {
+	g := New([]Player{pa, pb, pc}, PopeJoan())
+	r := g.init()
+	if want := r.widow[len(r.widow)-1].Suit(); r.trump != want {
+		t.Errorf("trump is %v, expected %v", r.trump, want)
+	}
+	for name, n := range g.Stake() {
+		if n != 3 {
+			t.Errorf("stake %q is %v, expected 3", name, n)
+		}
+	}
+	if g.kitty != 3 {
+		t.Errorf("kitty is %v, expected 3", g.kitty)
+	}
+	if !reflect.DeepEqual(r.dealt, r.deck) {
+		t.Errorf("dealt is %v, expected %v", r.dealt, r.deck)
+	}
+}
+
+func TestPopeJoanPlay(t *testing.T) {
+	la, lb := &listener{}, &listener{}
+	deck := make([]int, 52)
+	for i := range deck {
+		deck[i] = -1
+	}
+	deck[card.Spades.Rank(card.Jack)] = 0
+	deck[card.Spades.Rank(card.Queen)] = 0
+	deck[card.Spades.Rank(card.King)] = 0
+	deck[card.Clubs.Rank(card.Two)] = 1
+	r := indexed(&round{
+		g: &Game{
+			players: []Player{la, lb},
+			score:   []int{-8, -8},
+			stake:   []int{2, 2, 2, 2, 2, 2, 2},
+			kitty:   2,
+			rules:   PopeJoan(),
+		},
+		n:     []int{3, 1},
+		deck:  deck,
+		dealt: append([]int(nil), deck...),
+		widow: []card.Card{card.Diamonds.Rank(card.Nine)},
+		start: []int{0, 0},
+		trump: card.Spades,
+	})
+	r.play()
+	if score := []int{5, -7}; !reflect.DeepEqual(r.g.score, score) {
+		t.Errorf("score is %v, expected %v", r.g.score, score)
+	}
+	if stake := []int{0, 0, 0, 2, 0, 0, 0}; !reflect.DeepEqual(r.g.stake, stake) {
+		t.Errorf("stake is %v, expected %v", r.g.stake, stake)
+	}
+	want := []string{
+		"collect 1 Pope 2",
+		"collect 0 Jack 2",
+		"collect 0 Intrigue 2",
+		"collect 0 Queen 2",
+		"collect 0 Matrimony 2",
+		"collect 0 King 2",
+		"collect 0 Leftover 1",
+		"end 0",
+	}
+	if !reflect.DeepEqual(la.events, want) {
+		t.Errorf("events are %q, expected %q", la.events, want)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	queen, king := card.Spades.Rank(card.Queen), card.Spades.Rank(card.King)
+	matrimony := PopeJoan().Layout[1]
+	for name, test := range map[string]struct {
+		dealt, deck []int
+		pos         int
+		ok          bool
+	}{
+		"both":       {[]int{0, 0}, []int{-1, -1}, 0, true},
+		"other held": {[]int{0, 0}, []int{0, -1}, 0, false},
+		"split":      {[]int{1, 0}, []int{-1, -1}, 0, false},
+		"widow":      {[]int{-1, 0}, []int{-1, -1}, 0, false},
+	} {
+		r := &round{
+			dealt: make([]int, 52),
+			deck:  make([]int, 52),
+			trump: card.Spades,
+		}
+		r.dealt[queen], r.dealt[king] = test.dealt[0], test.dealt[1]
+		r.deck[queen], r.deck[king] = test.deck[0], test.deck[1]
+		if ok := r.claims(test.pos, &matrimony, king); ok != test.ok {
+			t.Errorf("claims(%q): got %v, expected %v", name, ok, test.ok)
+		}
+	}
+}
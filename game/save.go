@@ -0,0 +1,244 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// SaveVersion is the version of the format written by Save. Load reads only
+// this version.
+const SaveVersion = 2
+
+// saved is the format written by Save.
+type saved struct {
+	Version int
+
+	// Seats, Score, Stake, Kitty, Hands and Rules record the Game's fields
+	// of the same names.
+	Seats []int
+	Score []int
+	Stake []int
+	Kitty int
+	Hands int
+	Rules Rules
+
+	Record, Verify bool
+	Ledger         []Transfer
+
+	// Round records the hand in progress, if any.
+	Round *savedRound `json:",omitempty"`
+}
+
+// savedRound records a round that waits for a decision.
+type savedRound struct {
+	N      []int
+	Deck   []int
+	Played []card.Card
+	Run    []card.Card
+	Leader int
+	Widow  []card.Card
+	Start  []int
+	Dealt  []int
+	Trump  card.Suit
+
+	// From, Pos and Suits record the pending decision.
+	From, Pos int
+	Suits     []card.Suit
+}
+
+// Save returns an encoding of the state of a Game: its seating, scores,
+// stakes, Kitty, Rules and ledger, and the hand in progress, if any, which
+// must be waiting for a Player's decision. Save may be called from another
+// goroutine or from within the PlayMajor or ChooseSuit method of the Player
+// whose decision is pending; it returns an error if a hand is in progress at
+// any other point.
+//
+// The Game's source of randomness is not saved.
+func (g *Game) Save() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	s := saved{
+		Version: SaveVersion,
+		Seats:   g.seats,
+		Score:   g.score,
+		Stake:   g.stake,
+		Kitty:   g.kitty,
+		Hands:   g.hands,
+		Rules:   g.rules,
+		Record:  g.record,
+		Verify:  g.verify,
+		Ledger:  g.ledger,
+	}
+	if r := g.r; r != nil && r.live {
+		if !r.pending.ok {
+			return nil, errors.New("game: the hand in progress is not waiting for a decision")
+		}
+		s.Round = &savedRound{
+			N:      r.n,
+			Deck:   r.deck,
+			Played: r.played,
+			Run:    r.run,
+			Leader: r.leader,
+			Widow:  r.widow,
+			Start:  r.start,
+			Dealt:  r.dealt,
+			Trump:  r.trump,
+			From:   r.pending.from,
+			Pos:    r.pending.pos,
+			Suits:  r.pending.suits[:r.pending.n],
+		}
+	}
+	return json.Marshal(s)
+}
+
+// Load returns a Game restored from data written by Save. players lists the
+// Players in the order in which they were passed to New. If a hand was in
+// progress, call Resume to finish it.
+func Load(data []byte, players []Player) (*Game, error) {
+	var s saved
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("game: %w", err)
+	}
+	if s.Version != SaveVersion {
+		return nil, fmt.Errorf("game: unsupported version %v", s.Version)
+	}
+	n := len(players)
+	if err := validate(n, s.Rules); err != nil {
+		return nil, err
+	}
+	if err := distinct(players); err != nil {
+		return nil, err
+	}
+	if len(s.Seats) != n || len(s.Score) != n {
+		return nil, fmt.Errorf("game: saved Game does not have %v players", n)
+	}
+	if len(s.Stake) != len(s.Rules.Layout) {
+		return nil, fmt.Errorf("game: %v stakes for %v Pots", len(s.Stake), len(s.Rules.Layout))
+	}
+	g := &Game{
+		players: make([]Player, n),
+		score:   s.Score,
+		seats:   s.Seats,
+		kitty:   s.Kitty,
+		stake:   s.Stake,
+		rules:   s.Rules,
+		hands:   s.Hands,
+		record:  s.Record,
+		verify:  s.Verify,
+		ledger:  s.Ledger,
+	}
+	seated := make([]bool, n)
+	for pos, i := range s.Seats {
+		if i < 0 || i >= n || seated[i] {
+			return nil, fmt.Errorf("game: invalid seats %v", s.Seats)
+		}
+		seated[i] = true
+		g.players[pos] = players[i]
+	}
+	if s.Round != nil {
+		r, err := s.Round.round(g)
+		if err != nil {
+			return nil, err
+		}
+		g.r = r
+	}
+	return g, nil
+}
+
+// round returns the round recorded by sr, played by g.
+func (sr *savedRound) round(g *Game) (*round, error) {
+	n := len(g.players)
+	if len(sr.N) != n || len(sr.Start) != n {
+		return nil, fmt.Errorf("game: saved hand does not have %v players", n)
+	}
+	if len(sr.Deck) == 0 || len(sr.Deck)%52 != 0 || len(sr.Dealt) != len(sr.Deck) {
+		return nil, errors.New("game: invalid deck")
+	}
+	count := make([]int, n)
+	for i := range sr.Deck {
+		pos, dealt := sr.Deck[i], sr.Dealt[i]
+		if pos < -1 || pos >= n || dealt < -1 || dealt >= n || pos != -1 && pos != dealt {
+			return nil, errors.New("game: invalid deck")
+		}
+		if pos != -1 {
+			count[pos]++
+		}
+	}
+	for pos := range count {
+		if count[pos] != sr.N[pos] {
+			return nil, fmt.Errorf("game: position %v holds %v cards, not %v", pos, count[pos], sr.N[pos])
+		}
+	}
+	for _, cs := range [][]card.Card{sr.Played, sr.Run, sr.Widow} {
+		for _, c := range cs {
+			if c < 0 || c >= 52 {
+				return nil, fmt.Errorf("game: invalid card %d", int(c))
+			}
+		}
+	}
+	for _, pos := range []int{sr.Leader, sr.From, sr.Pos} {
+		if pos < 0 || pos >= n {
+			return nil, fmt.Errorf("game: invalid position %v", pos)
+		}
+	}
+	if sr.Trump < 0 || sr.Trump > card.Hearts {
+		return nil, fmt.Errorf("game: invalid trump %v", sr.Trump)
+	}
+	r := &round{
+		g:      g,
+		n:      sr.N,
+		deck:   sr.Deck,
+		played: sr.Played,
+		run:    sr.Run,
+		leader: sr.Leader,
+		widow:  sr.Widow,
+		start:  sr.Start,
+		dealt:  sr.Dealt,
+		trump:  sr.Trump,
+		live:   true,
+		hand:   make([][]card.Card, n),
+	}
+	r.index()
+	if len(sr.Suits) == 0 || len(sr.Suits) > len(r.pending.suits) {
+		return nil, fmt.Errorf("game: invalid pending suits %v", sr.Suits)
+	}
+	for _, s := range sr.Suits {
+		if s < 0 || s > card.Hearts {
+			return nil, fmt.Errorf("game: invalid pending suits %v", sr.Suits)
+		}
+		if _, ok := r.lowest(sr.Pos, s); !ok {
+			return nil, fmt.Errorf("game: position %v holds no %v", sr.Pos, s)
+		}
+	}
+	r.pending = decision{ok: true, from: sr.From, pos: sr.Pos}
+	r.pending.n = copy(r.pending.suits[:], sr.Suits)
+	for c := card.Card(0); c < 52; c++ {
+		for i := int(c); i < len(r.deck); i += 52 {
+			if pos := r.deck[i]; pos != -1 {
+				r.hand[pos] = append(r.hand[pos], c)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Resume finishes a hand restored by Load. It calls each Player's Init method
+// with the cards they hold, asks the Player whose decision is pending to make
+// it, and plays the rest of the hand as Play does. Resume does nothing if no
+// hand is waiting for a decision.
+func (g *Game) Resume() {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	r := g.r
+	if r == nil || !r.live || !r.pending.ok {
+		return
+	}
+	r.steps = nil
+	r.inform()
+	d := r.pending
+	lead, _ := r.decide(d)
+	r.proceed(d.from, lead)
+}
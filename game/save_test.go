@@ -0,0 +1,168 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// saver is a Player that saves its Game at its first decision.
+type saver struct {
+	minor
+	g    *Game
+	data []byte
+	err  error
+}
+
+func (s *saver) PlayMajor(t TableState, color card.Color) bool {
+	if s.data == nil && s.err == nil {
+		s.data, s.err = s.g.Save()
+	}
+	return s.minor.PlayMajor(t, color)
+}
+
+// noter is a Player that saves its Game whenever it is informed of a card play.
+type noter struct {
+	minor
+	g   *Game
+	err error
+}
+
+func (n *noter) Note(int, card.Card) { _, n.err = n.g.Save() }
+
+func TestSaveResume(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		s := &saver{}
+		players := []Player{s, pb, pc, pd}
+		g := New(players, Michigan())
+		s.g = g
+		g.Seed(seed)
+		g.Play()
+		if s.err != nil {
+			t.Fatalf("Save(%v): %v", seed, s.err)
+		}
+		if s.data == nil {
+			continue
+		}
+		h, err := Load(s.data, players)
+		if err != nil {
+			t.Fatalf("Load(%v): %v", seed, err)
+		}
+		h.Resume()
+		if !reflect.DeepEqual(h.Score(), g.Score()) || !reflect.DeepEqual(h.Stake(), g.Stake()) || h.Kitty() != g.Kitty() {
+			t.Errorf("Resume(%v): Score %v, Stake %v, Kitty %v; expected %v, %v, %v", seed,
+				h.Score(), h.Stake(), h.Kitty(), g.Score(), g.Stake(), g.Kitty(),
+			)
+		}
+		if !reflect.DeepEqual(h.r.played, g.r.played) {
+			t.Errorf("Resume(%v): played %v, expected %v", seed, h.r.played, g.r.played)
+		}
+	}
+}
+
+func TestSaveBetweenHands(t *testing.T) {
+	players := []Player{pa, pb, pc}
+	g := New(players, Newmarket())
+	g.Seed(1)
+	g.Record(true)
+	g.Play()
+	data, err := g.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Load(data, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.r != nil {
+		t.Errorf("Load: round is %+v, expected none", h.r)
+	}
+	h.r, h.rng = g.r, g.rng
+	if !reflect.DeepEqual(h, g) {
+		t.Errorf("Load: Game is %+v, expected %+v", h, g)
+	}
+}
+
+func TestSaveInProgress(t *testing.T) {
+	nt := &noter{}
+	g := New([]Player{nt, pb}, Michigan())
+	nt.g = g
+	g.Play()
+	if nt.err == nil {
+		t.Errorf("Save: got no error during a hand not waiting for a decision")
+	}
+}
+
+// TestSaveRecord saves a Game while another goroutine changes its recording
+// and verification modes. Run it with the race detector.
+func TestSaveRecord(t *testing.T) {
+	g := New([]Player{pa, pb}, Michigan())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			g.Record(i%2 == 0)
+			g.Verify(i%2 == 1)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := g.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+func TestLoadErrors(t *testing.T) {
+	valid := func() saved {
+		return saved{
+			Version: SaveVersion,
+			Seats:   []int{1, 0},
+			Score:   []int{0, 0},
+			Stake:   []int{0, 0, 0, 0, 0},
+			Rules:   Michigan(),
+		}
+	}
+	for name, f := range map[string]func(*saved){
+		"version": func(s *saved) { s.Version++ },
+		"players": func(s *saved) { s.Seats, s.Score = []int{0, 1, 2}, []int{0, 0, 0} },
+		"seats":   func(s *saved) { s.Seats = []int{1, 1} },
+		"stake":   func(s *saved) { s.Stake = []int{0} },
+		"empty":   func(s *saved) { s.Rules.Deck = nil },
+		"card":    func(s *saved) { s.Rules.Deck = append(card.NewDeck(), 52) },
+		"layout":  func(s *saved) { s.Rules.Layout[0].Cards = []card.Card{-1} },
+		"pot":     func(s *saved) { s.Rules.Layout[1].Name = s.Rules.Layout[0].Name },
+		"deck": func(s *saved) {
+			s.Round = &savedRound{N: []int{0, 0}, Start: []int{0, 0}, Deck: []int{0}, Dealt: []int{0}}
+		},
+	} {
+		s := valid()
+		f(&s)
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(data, []Player{pa, pb}); err == nil {
+			t.Errorf("Load(%q): got no error", name)
+		}
+	}
+	if _, err := Load([]byte("{"), []Player{pa, pb}); err == nil {
+		t.Errorf("Load: got no error for invalid data")
+	}
+	data, _ := json.Marshal(valid())
+	if _, err := Load(data, []Player{pa, pb}); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+}
+
+func TestLoadDuplicate(t *testing.T) {
+	data, err := New([]Player{pa, pb}, Michigan()).Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(data, []Player{pa, pa}); err == nil {
+		t.Errorf("Load: got no error for a Player passed twice")
+	}
+}
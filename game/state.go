@@ -0,0 +1,84 @@
+package game
+
+import "github.com/dkmccandless/tripoli/card"
+
+// A TableState is a snapshot of the public state of a hand of Tripoli.
+// Positions are counted from the dealer's left, as in Player.Init.
+//
+// A Viewer instead receives a TableState that is a view whose slices are
+// shared with the engine: they must not be modified, and remain valid only
+// until the method returns. A Viewer that retains a TableState must Clone it.
+type TableState struct {
+	// Stake records the values of the Pots of the layout, in the order of
+	// the Rules' Layout.
+	Stake []int
+
+	// Kitty is the value of the Kitty.
+	Kitty int
+
+	// Score records each player's score by position.
+	Score []int
+
+	// Count records the number of cards in each player's hand by position.
+	Count []int
+
+	// Played records the cards played so far in the hand, in order.
+	Played []card.Card
+
+	// Run records the cards played so far in the current run, in order.
+	// While the next lead is being decided, it records the run that has
+	// just stopped.
+	Run []card.Card
+
+	// Leader is the position of the player who led the current run or,
+	// while the next lead is being decided, the player who must lead it.
+	Leader int
+
+	// Trump is the trump suit, if the Rules turn up trumps.
+	Trump card.Suit
+}
+
+// Clone returns a copy of a TableState that shares no memory with it.
+func (t TableState) Clone() TableState {
+	t.Stake = append([]int(nil), t.Stake...)
+	t.Score = append([]int(nil), t.Score...)
+	t.Count = append([]int(nil), t.Count...)
+	t.Played = append([]card.Card(nil), t.Played...)
+	t.Run = append([]card.Card(nil), t.Run...)
+	return t
+}
+
+// State returns a snapshot of the public state of the hand in progress, or of
+// the most recent hand if none is in progress. Before the first hand, only the
+// stakes and the Kitty are reported.
+func (g *Game) State() TableState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.r == nil {
+		return TableState{Stake: g.stake, Kitty: g.kitty}.Clone()
+	}
+	return g.r.state().Clone()
+}
+
+// snapshot returns the TableState that the Player at the indicated position
+// receives: a view if it is a Viewer, and otherwise a snapshot.
+func (r *round) snapshot(pos int) TableState {
+	if views(r.g.players[pos]) {
+		return r.state()
+	}
+	return r.state().Clone()
+}
+
+// state returns a view of the round's public state.
+func (r *round) state() TableState {
+	return TableState{
+		Stake:  r.g.stake,
+		Kitty:  r.g.kitty,
+		Score:  r.g.score,
+		Count:  r.n,
+		Played: r.played,
+		Run:    r.run,
+		Leader: r.leader,
+		Trump:  r.trump,
+	}
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+func TestGameState(t *testing.T) {
+	g := New([]Player{pa, pb}, Michigan())
+	g.kitty = 2
+	want := TableState{
+		Stake: []int{0, 0, 0, 0, 0},
+		Kitty: 2,
+	}
+	if s := g.State(); !reflect.DeepEqual(s, want) {
+		t.Errorf("State before the first hand: got %+v, expected %+v", s, want)
+	}
+	g = New([]Player{pa, pb}, Michigan())
+	g.Play()
+	s := g.State()
+	if len(s.Score) != 2 || len(s.Count) != 2 {
+		t.Fatalf("State after a hand: got %+v", s)
+	}
+	var total int
+	for _, n := range s.Score {
+		total += n
+	}
+	for _, n := range s.Stake {
+		total += n
+	}
+	if total += s.Kitty; total != 0 {
+		t.Errorf("State after a hand: chips total %v, expected 0", total)
+	}
+}
+
+func TestState(t *testing.T) {
+	r := &round{
+		g: &Game{
+			players: []Player{pb, pc, pa},
+			score:   []int{4, -1, -3},
+			rules:   Michigan(),
+			stake:   counters(3, 0, 3, 3, 0),
+			kitty:   2,
+		},
+		n:      []int{4, 5, 3},
+		played: []card.Card{0, 1, 2, 47, 48},
+		run:    []card.Card{47, 48},
+		leader: 2,
+	}
+	want := TableState{
+		Stake:  []int{3, 0, 3, 3, 0},
+		Kitty:  2,
+		Score:  []int{4, -1, -3},
+		Count:  []int{4, 5, 3},
+		Played: []card.Card{0, 1, 2, 47, 48},
+		Run:    []card.Card{47, 48},
+		Leader: 2,
+	}
+	if s := r.state(); !reflect.DeepEqual(s, want) {
+		t.Errorf("state: got %+v, expected %+v", s, want)
+	}
+	r.g.r = r
+	s := r.g.State()
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("State: got %+v, expected %+v", s, want)
+	}
+	s.Stake[0] = 0
+	s.Score[0] = 0
+	s.Count[0] = 0
+	s.Played[0] = 51
+	if r.g.stake[0] != 3 || r.g.score[0] != 4 || r.n[0] != 4 || r.played[0] != 0 {
+		t.Errorf("modifying the snapshot modified the round: %+v", r)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := TableState{
+		Stake:  []int{1, 2},
+		Kitty:  3,
+		Score:  []int{-1, 1},
+		Count:  []int{4, 5},
+		Played: []card.Card{6, 7},
+		Run:    []card.Card{7},
+		Leader: 1,
+		Trump:  card.Spades,
+	}
+	c := s.Clone()
+	if !reflect.DeepEqual(c, s) {
+		t.Errorf("Clone: got %+v, expected %+v", c, s)
+	}
+	c.Stake[0], c.Score[0], c.Count[0], c.Played[0], c.Run[0] = 0, 0, 0, 0, 0
+	if s.Stake[0] != 1 || s.Score[0] != -1 || s.Count[0] != 4 || s.Played[0] != 6 || s.Run[0] != 7 {
+		t.Errorf("modifying the Clone modified the TableState: %+v", s)
+	}
+}
+
+// watcher records copies of the TableStates it receives.
+type watcher struct {
+	minor
+	states []TableState
+}
+
+func (w *watcher) PlayMajor(t TableState, _ card.Color) bool {
+	w.states = append(w.states, t.Clone())
+	return false
+}
+
+func TestNextLeadState(t *testing.T) {
+	w := &watcher{}
+	r := indexed(&round{
+		g: &Game{
+			players: []Player{pa, w},
+			score:   []int{0, 0},
+			rules:   Michigan(),
+		},
+		n: []int{1, 2},
+		deck: []int{
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0,
+			-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 1,
+		},
+		played: []card.Card{12},
+		run:    []card.Card{12},
+	})
+	r.nextLead(0, []card.Suit{card.Diamonds, card.Hearts})
+	want := []TableState{{
+		Kitty:  1,
+		Score:  []int{-1, 0},
+		Count:  []int{1, 2},
+		Played: []card.Card{12},
+		Run:    []card.Card{12},
+		Leader: 1,
+	}}
+	if !reflect.DeepEqual(w.states, want) {
+		t.Errorf("nextLead: PlayMajor received %+v, expected %+v", w.states, want)
+	}
+}
+
+// keeper retains the hands, stakes and TableStates it receives, and copies of
+// them.
+type keeper struct {
+	minor
+	kept, copies []TableState
+}
+
+func (k *keeper) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	// Record the hand as Played, to compare it as the TableStates are.
+	t := TableState{Stake: stake, Played: hand}
+	k.kept = append(k.kept, t)
+	k.copies = append(k.copies, t.Clone())
+}
+
+func (k *keeper) PlayMajor(t TableState, _ card.Color) bool {
+	k.kept = append(k.kept, t)
+	k.copies = append(k.copies, t.Clone())
+	return false
+}
+
+func TestSnapshots(t *testing.T) {
+	k := &keeper{}
+	g := New([]Player{k, pa, pb, pc}, Michigan())
+	g.Seed(1)
+	for i := 0; i < 20; i++ {
+		g.Play()
+	}
+	if len(k.kept) <= 20 {
+		t.Fatalf("keeper received %v calls, expected more than 20", len(k.kept))
+	}
+	if !reflect.DeepEqual(k.kept, k.copies) {
+		t.Error("the hands, stakes and TableStates received by a Player changed after the calls returned")
+	}
+}
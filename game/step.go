@@ -0,0 +1,192 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// An EventKind distinguishes the kinds of Events.
+type EventKind int
+
+//go:generate stringer -type=EventKind
+const (
+	// PlayEvent reports that a player played a Card.
+	PlayEvent EventKind = iota
+
+	// PassEvent reports that a player could not lead from any of the Suits,
+	// and that control of the lead passed.
+	PassEvent
+
+	// PayKittyEvent reports that a player paid N chips into the Kitty.
+	PayKittyEvent
+
+	// CollectEvent reports that a player collected the N chips in a Pot,
+	// or in LeftoverPot from another player.
+	CollectEvent
+
+	// DecisionEvent reports that a player must decide from which of the
+	// Suits to lead. The hand waits until Decide is called.
+	DecisionEvent
+
+	// EndEvent reports that the hand is over, and its Result.
+	EndEvent
+)
+
+// An Event describes something that happened during a hand played step by
+// step. The fields that are meaningful depend on its Kind.
+type Event struct {
+	Kind EventKind
+
+	// Pos is the position of the player concerned, counting from the
+	// dealer's left. It is the position of the winner, or -1, for an
+	// EndEvent.
+	Pos int
+
+	Card   card.Card
+	Suits  []card.Suit
+	Pot    string
+	N      int
+	Result Result
+}
+
+// A Hand is a hand of Tripoli played step by step, for callers that drive the
+// game from an event loop instead of from the methods of each Player.
+//
+// A Hand informs the Game's Players of the deal and of each event as Play
+// does, but does not call PlayMajor or ChooseSuit. Instead, Next reports each
+// decision as a DecisionEvent, and the hand waits until Decide is called.
+// A Hand is abandoned if its Game plays or begins another hand.
+type Hand struct {
+	g *Game
+	r *round
+
+	// number is the number of the Game's hand that the Hand plays.
+	number int
+
+	// events holds the events that Next has not yet returned.
+	events []Event
+
+	// decision is the decision for which the Hand waits, if waiting is true.
+	decision Event
+	waiting  bool
+
+	// clone reports whether the Hand is a copy made by Clone, and auto
+	// whether its decisions are made by its Players.
+	clone, auto bool
+}
+
+// Begin deals a hand as Play does, plays it until the first decision, and
+// returns it.
+func (g *Game) Begin() *Hand {
+	g.hand.Lock()
+	defer g.hand.Unlock()
+	h := &Hand{g: g}
+	h.r = g.init()
+	h.r.steps = h
+	h.number = g.hands
+	h.r.play()
+	return h
+}
+
+// Next returns the next event of the hand and true, or false if the hand is
+// over and every event has been returned. While the hand waits for a
+// decision, Next returns the DecisionEvent once all earlier events have been
+// returned.
+func (h *Hand) Next() (Event, bool) {
+	if len(h.events) > 0 {
+		e := h.events[0]
+		h.events = h.events[1:]
+		return e, true
+	}
+	if h.waiting {
+		return h.decision, true
+	}
+	return Event{}, false
+}
+
+// Decide makes the pending decision by choosing the suit from which the
+// deciding player leads, and plays the hand until the next decision.
+// Decide panics if the hand is not waiting for a decision or the suit is not
+// one of the DecisionEvent's Suits.
+func (h *Hand) Decide(s card.Suit) {
+	h.g.hand.Lock()
+	defer h.g.hand.Unlock()
+	if !h.waiting || h.g.hands != h.number || h.r.steps != h {
+		panic("game: Hand is not waiting for a decision")
+	}
+	ok := false
+	for _, t := range h.decision.Suits {
+		ok = ok || s == t
+	}
+	if !ok {
+		panic(fmt.Sprintf("game: Decide(%v), expected one of %v", s, h.decision.Suits))
+	}
+	h.waiting = false
+	r := h.r
+	d := r.pending
+	r.g.mu.Lock()
+	r.pending = decision{}
+	r.g.mu.Unlock()
+	lead, _ := r.lowest(d.pos, s)
+	r.proceed(d.from, lead)
+}
+
+// State returns a snapshot of the public state of the hand.
+// State panics if the Hand has been abandoned.
+func (h *Hand) State() TableState {
+	h.g.mu.RLock()
+	defer h.g.mu.RUnlock()
+	h.current()
+	return h.r.state().Clone()
+}
+
+// Player returns the Player at the indicated position in the hand.
+// Player panics if the Hand has been abandoned.
+func (h *Hand) Player(pos int) Player {
+	h.g.mu.RLock()
+	defer h.g.mu.RUnlock()
+	h.current()
+	return h.g.players[pos]
+}
+
+// Cards returns the cards held by the player at the indicated position, in
+// order. Cards panics if the Hand has been abandoned.
+func (h *Hand) Cards(pos int) []card.Card {
+	h.g.mu.RLock()
+	defer h.g.mu.RUnlock()
+	h.current()
+	var cs []card.Card
+	for c := card.Card(0); c < 52; c++ {
+		for i := int(c); i < len(h.r.deck); i += 52 {
+			if h.r.deck[i] == pos {
+				cs = append(cs, c)
+			}
+		}
+	}
+	return cs
+}
+
+// current panics if the Hand has been abandoned, since its Game reuses the
+// memory of its round for the next hand. The caller must hold h.g.mu.
+func (h *Hand) current() {
+	if h.g.hands != h.number || h.r.steps != h {
+		panic("game: Hand has been abandoned for a later hand")
+	}
+}
+
+// push adds an event to the Hand's events. A clone keeps only its decisions
+// and its EndEvent.
+func (h *Hand) push(e Event) {
+	if h.clone && e.Kind != DecisionEvent && e.Kind != EndEvent {
+		return
+	}
+	h.events = append(h.events, e)
+}
+
+// wait adds a DecisionEvent to the Hand's events and records that the Hand
+// waits for it.
+func (h *Hand) wait(e Event) {
+	h.push(e)
+	h.decision, h.waiting = e, true
+}
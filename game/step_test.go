@@ -0,0 +1,139 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+// step plays a Hand to its end, making each decision as the deciding Player
+// would, and returns its events.
+func step(h *Hand) []Event {
+	var events []Event
+	for {
+		e, ok := h.Next()
+		if !ok {
+			return events
+		}
+		events = append(events, e)
+		if e.Kind != DecisionEvent {
+			continue
+		}
+		p := h.Player(e.Pos)
+		if sc, ok := p.(SuitChooser); ok && e.Suits[0].Color() != e.Suits[len(e.Suits)-1].Color() {
+			h.Decide(sc.ChooseSuit(h.State(), e.Suits))
+			continue
+		}
+		color := e.Suits[0].Color()
+		if p.PlayMajor(h.State(), color) {
+			h.Decide(color.Major())
+		} else {
+			h.Decide(color.Minor())
+		}
+	}
+}
+
+func TestBegin(t *testing.T) {
+	for name, rules := range map[string]Rules{
+		"Michigan":  Michigan(),
+		"Newmarket": Newmarket(),
+		"PopeJoan":  PopeJoan(),
+	} {
+		for seed := int64(0); seed < 10; seed++ {
+			la, lb := &listener{}, &listener{}
+			g := New([]Player{la, pc, lb, pd}, rules)
+			g.Seed(seed)
+			g.Play()
+			want, played := g.Score(), append([]card.Card(nil), g.r.played...)
+			wantEvents := append([]string(nil), la.events...)
+
+			la.events = nil
+			h := New([]Player{la, pc, lb, pd}, rules)
+			h.Seed(seed)
+			events := step(h.Begin())
+			if !reflect.DeepEqual(h.Score(), want) || !reflect.DeepEqual(h.r.played, played) {
+				t.Errorf("Begin(%q, %v): Score %v after playing %v, expected %v after %v",
+					name, seed, h.Score(), h.r.played, want, played,
+				)
+			}
+			if !reflect.DeepEqual(la.events, wantEvents) {
+				t.Errorf("Begin(%q, %v): Listener events are %q, expected %q", name, seed, la.events, wantEvents)
+			}
+			var got []string
+			var plays []card.Card
+			for _, e := range events {
+				switch e.Kind {
+				case PlayEvent:
+					plays = append(plays, e.Card)
+				case PassEvent:
+					got = append(got, fmt.Sprintf("pass %v %v", e.Pos, e.Suits))
+				case PayKittyEvent:
+					got = append(got, fmt.Sprintf("kitty %v %v", e.Pos, e.N))
+				case CollectEvent:
+					got = append(got, fmt.Sprintf("collect %v %v %v", e.Pos, e.Pot, e.N))
+				case EndEvent:
+					got = append(got, fmt.Sprintf("end %v", e.Pos))
+				}
+			}
+			if !reflect.DeepEqual(got, wantEvents) || !reflect.DeepEqual(plays, played) {
+				t.Errorf("Begin(%q, %v): events are %q after playing %v, expected %q after %v",
+					name, seed, got, plays, wantEvents, played,
+				)
+			}
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	g := New([]Player{pa, pb, pc, pd}, Michigan())
+	var h *Hand
+	var e Event
+	for seed := int64(0); e.Kind != DecisionEvent; seed++ {
+		g.Seed(seed)
+		h = g.Begin()
+		for ok := true; ok && e.Kind != DecisionEvent; e, ok = h.Next() {
+		}
+	}
+	if again, _ := h.Next(); !reflect.DeepEqual(again, e) {
+		t.Errorf("Next: got %+v while waiting, expected %+v", again, e)
+	}
+	if len(e.Suits) != 2 || e.Suits[0].Color() != e.Suits[1].Color() {
+		t.Errorf("Next: DecisionEvent Suits are %v, expected the suits of one color", e.Suits)
+	}
+	for name, f := range map[string]func(){
+		"suit": func() { h.Decide(e.Suits[0].Color().Opp().Minor()) },
+		"abandoned": func() {
+			h2 := g.Begin()
+			g.Play()
+			h2.Decide(card.Clubs)
+		},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Decide(%q): did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+
+	// A Hand's methods panic once its Game has begun another hand.
+	g.Play()
+	for name, f := range map[string]func(){
+		"State":  func() { h.State() },
+		"Cards":  func() { h.Cards(0) },
+		"Player": func() { h.Player(0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v: did not panic on an abandoned Hand", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
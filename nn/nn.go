@@ -0,0 +1,229 @@
+// Package nn provides a Player that decides by evaluating a small neural
+// network, a multilayer perceptron, over the observations of package env, and
+// a means of training the network on simulated hands.
+//
+// A Network's output is the logit of the probability that leading from the
+// major suit gains more chips than leading from the minor suit. Networks are
+// saved and loaded as JSON or in a compact binary format.
+package nn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// A Layer is a fully connected layer of a Network.
+type Layer struct {
+	// In and Out are the numbers of the Layer's inputs and outputs.
+	In, Out int
+
+	// Weights holds the weights of each output in turn, In weights for each,
+	// and Biases holds the bias of each output.
+	Weights []float32
+	Biases  []float32
+}
+
+// A Network is a multilayer perceptron. Each Layer but the last applies a
+// rectified linear activation to its outputs, and the last Layer has a single
+// output. A Network may be encoded and decoded with the encoding/json
+// package.
+type Network struct {
+	Layers []Layer
+}
+
+// New returns a Network with the given number of inputs and hidden Layers of
+// the given sizes, with random weights drawn from a random number generator
+// seeded with the given value. New panics if any size is not positive.
+func New(inputs int, hidden []int, seed int64) *Network {
+	rng := rand.New(rand.NewSource(seed))
+	net := &Network{}
+	in := inputs
+	for _, out := range append(append([]int(nil), hidden...), 1) {
+		if in <= 0 || out <= 0 {
+			panic("nn: layer sizes must be positive")
+		}
+		l := Layer{
+			In:      in,
+			Out:     out,
+			Weights: make([]float32, in*out),
+			Biases:  make([]float32, out),
+		}
+		// He initialization suits rectified linear activations.
+		scale := math.Sqrt(2 / float64(in))
+		for i := range l.Weights {
+			l.Weights[i] = float32(rng.NormFloat64() * scale)
+		}
+		net.Layers = append(net.Layers, l)
+		in = out
+	}
+	return net
+}
+
+// Inputs returns the number of the Network's inputs.
+func (net *Network) Inputs() int { return net.Layers[0].In }
+
+// Logit evaluates the Network on the input x and returns its output.
+// Logit panics if x does not have length net.Inputs().
+func (net *Network) Logit(x []float32) float32 {
+	if len(x) != net.Inputs() {
+		panic(fmt.Sprintf("nn: input of length %v, expected %v", len(x), net.Inputs()))
+	}
+	for i, l := range net.Layers {
+		x = l.apply(x, make([]float32, l.Out), i < len(net.Layers)-1)
+	}
+	return x[0]
+}
+
+// Major reports whether the Network favors leading from the major suit for
+// the input x.
+func (net *Network) Major(x []float32) bool { return net.Logit(x) > 0 }
+
+// apply evaluates the Layer on the input x, stores its outputs in y, and
+// returns y. It applies the activation if relu is true.
+func (l *Layer) apply(x, y []float32, relu bool) []float32 {
+	for o := range y {
+		sum := l.Biases[o]
+		w := l.Weights[o*l.In : (o+1)*l.In]
+		for i, v := range x {
+			sum += w[i] * v
+		}
+		if sum < 0 && relu {
+			sum = 0
+		}
+		y[o] = sum
+	}
+	return y
+}
+
+// check returns an error if the Network is malformed.
+func (net *Network) check() error {
+	if len(net.Layers) == 0 {
+		return errors.New("nn: network has no layers")
+	}
+	for i, l := range net.Layers {
+		switch {
+		case l.In <= 0 || l.Out <= 0:
+			return fmt.Errorf("nn: layer %v has size %vx%v", i, l.In, l.Out)
+		case i > 0 && l.In != net.Layers[i-1].Out:
+			return fmt.Errorf("nn: layer %v has %v inputs, expected %v", i, l.In, net.Layers[i-1].Out)
+		case len(l.Weights) != l.In*l.Out || len(l.Biases) != l.Out:
+			return fmt.Errorf("nn: layer %v has %v weights and %v biases, expected %v and %v", i, len(l.Weights), len(l.Biases), l.In*l.Out, l.Out)
+		}
+	}
+	if out := net.Layers[len(net.Layers)-1].Out; out != 1 {
+		return fmt.Errorf("nn: network has %v outputs, expected 1", out)
+	}
+	return nil
+}
+
+// magic begins the binary encoding of a Network, followed by a version number.
+const (
+	magic   = "TRNN"
+	version = 1
+)
+
+// MarshalBinary encodes the Network in a binary format: magic, version and the
+// number of Layers, then each Layer's In and Out, Weights and Biases, all
+// little-endian 32-bit values.
+func (net *Network) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(magic)
+	w := func(v any) { binary.Write(&b, binary.LittleEndian, v) }
+	w(uint32(version))
+	w(uint32(len(net.Layers)))
+	for _, l := range net.Layers {
+		w(uint32(l.In))
+		w(uint32(l.Out))
+		w(l.Weights)
+		w(l.Biases)
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a Network encoded by MarshalBinary.
+func (net *Network) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return errors.New("nn: not a binary network")
+	}
+	r := bytes.NewReader(data[len(magic):])
+	var err error
+	read := func(v any) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	var ver, n uint32
+	read(&ver)
+	read(&n)
+	if err == nil && ver != version {
+		return fmt.Errorf("nn: binary network version %v, expected %v", ver, version)
+	}
+	var layers []Layer
+	for i := 0; err == nil && i < int(n); i++ {
+		var in, out uint32
+		read(&in)
+		read(&out)
+		// Guard against allocating for sizes that the data cannot hold.
+		if err == nil && uint64(in)*uint64(out)+uint64(out) > uint64(r.Len())/4 {
+			return errors.New("nn: truncated binary network")
+		}
+		l := Layer{In: int(in), Out: int(out), Weights: make([]float32, in*out), Biases: make([]float32, out)}
+		read(l.Weights)
+		read(l.Biases)
+		layers = append(layers, l)
+	}
+	switch {
+	case err != nil:
+		return fmt.Errorf("nn: malformed binary network: %v", err)
+	case r.Len() > 0:
+		return errors.New("nn: trailing data after binary network")
+	}
+	dec := Network{Layers: layers}
+	if err := dec.check(); err != nil {
+		return err
+	}
+	*net = dec
+	return nil
+}
+
+// Load reads a Network from the named file, in the binary format of
+// MarshalBinary or as JSON.
+func Load(name string) (*Network, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	net := &Network{}
+	if bytes.HasPrefix(data, []byte(magic)) {
+		err = net.UnmarshalBinary(data)
+	} else if err = json.Unmarshal(data, net); err == nil {
+		err = net.check()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+	return net, nil
+}
+
+// Save writes the Network to the named file, as JSON if the name ends in
+// ".json" and otherwise in the binary format of MarshalBinary.
+func (net *Network) Save(name string) error {
+	var data []byte
+	var err error
+	if strings.HasSuffix(name, ".json") {
+		data, err = json.Marshal(net)
+	} else {
+		data, err = net.MarshalBinary()
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
@@ -0,0 +1,173 @@
+package nn
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/env"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// tiny is a Network with two inputs and a hidden Layer of two outputs.
+var tiny = &Network{Layers: []Layer{
+	{In: 2, Out: 2, Weights: []float32{1, -1, -1, 1}, Biases: []float32{0, 0.5}},
+	{In: 2, Out: 1, Weights: []float32{2, 3}, Biases: []float32{-1}},
+}}
+
+func TestLogit(t *testing.T) {
+	for x, want := range map[[2]float32]float32{
+		{0, 0}: 0.5,
+		{1, 0}: 1,
+		{0, 1}: 3.5,
+		{2, 2}: 0.5,
+	} {
+		if got := tiny.Logit(x[:]); got != want {
+			t.Errorf("Logit(%v) = %v, expected %v", x, got, want)
+		}
+	}
+	net := New(5, []int{4, 3}, 1)
+	if err := net.check(); err != nil || net.Inputs() != 5 || len(net.Layers) != 3 {
+		t.Errorf("New(5, [4 3]) = %+v: %v", net, err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	net := New(env.Size(game.Michigan()), []int{8}, 1)
+	for _, name := range []string{"net.json", "net.bin"} {
+		name = filepath.Join(dir, name)
+		if err := net.Save(name); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(name)
+		if err != nil || !reflect.DeepEqual(got, net) {
+			t.Errorf("Load(%v) = %+v, %v, expected %+v", name, got, err, net)
+		}
+	}
+
+	bin, _ := tiny.MarshalBinary()
+	for name, data := range map[string][]byte{
+		"truncated": bin[:len(bin)-1],
+		"trailing":  append(append([]byte(nil), bin...), 0),
+		"version":   append([]byte(magic+"\x02"), bin[len(magic)+1:]...),
+		"json":      []byte(`{"Layers":[{"In":2,"Out":1,"Weights":[1],"Biases":[0]}]}`),
+		"outputs":   []byte(`{"Layers":[{"In":1,"Out":2,"Weights":[1,1],"Biases":[0,0]}]}`),
+		"empty":     []byte(`{}`),
+	} {
+		file := filepath.Join(dir, name)
+		os.WriteFile(file, data, 0o644)
+		if _, err := Load(file); err == nil || !strings.Contains(err.Error(), "nn: ") {
+			t.Errorf("Load(%v): error %v", name, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := Config{Rules: game.Michigan(), Players: 4, Hands: 25, Rollouts: 2, Seed: 3}
+	var want []Sample
+	for _, workers := range []int{1, 4} {
+		cfg.Workers = workers
+		samples := Generate(cfg)
+		if workers == 1 {
+			want = samples
+			continue
+		}
+		if !reflect.DeepEqual(samples, want) {
+			t.Errorf("Generate with %v workers differs from Generate with 1", workers)
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("Generate returned no Samples")
+	}
+	for _, s := range want {
+		if len(s.Obs) != env.Size(cfg.Rules) {
+			t.Fatalf("Sample observation of length %v, expected %v", len(s.Obs), env.Size(cfg.Rules))
+		}
+	}
+
+	var b bytes.Buffer
+	if err := WriteSamples(&b, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadSamples(&b); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSamples: %v Samples, %v", len(got), err)
+	}
+
+	cfg.Policy = New(env.Size(cfg.Rules), []int{4}, 1)
+	if len(Generate(cfg)) == 0 {
+		t.Error("Generate with a Policy returned no Samples")
+	}
+}
+
+func TestTrain(t *testing.T) {
+	// The major suit is better exactly when the first input exceeds the
+	// second.
+	var samples []Sample
+	for i := 0; i < 1000; i++ {
+		x := []float32{float32(i%10) / 10, float32(i%7) / 7, float32(i % 3)}
+		s := Sample{Obs: x, Gain: [2]float32{1, 0}}
+		if x[0] > x[1] {
+			s.Gain = [2]float32{0, 1}
+		}
+		samples = append(samples, s)
+	}
+	net := New(3, []int{8}, 1)
+	tr := NewTrainer(net, 1)
+	tr.Rate = 0.01
+	if loss := tr.Epoch(nil); loss != 0 {
+		t.Errorf("Epoch(nil) = %v, expected 0", loss)
+	}
+	first := tr.Epoch(samples)
+	var last float64
+	for i := 0; i < 100; i++ {
+		last = tr.Epoch(samples)
+	}
+	if last >= first/2 {
+		t.Errorf("loss fell from %v to %v", first, last)
+	}
+	if r := Regret(net, samples); r > 0.05 {
+		t.Errorf("Regret = %v, expected at most 0.05", r)
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	r := game.Michigan()
+	net := New(env.Size(r), []int{4}, 1)
+	name := filepath.Join(t.TempDir(), "net.bin")
+	if err := net.Save(name); err != nil {
+		t.Fatal(err)
+	}
+	specs, err := bots.Parse("nn:weights=" + name + ",nn:weights=" + name + ",minor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	players, err := specs.New(r, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(players[0].(*Player).net, net) {
+		t.Error("nn bot did not load its Network")
+	}
+	g := game.New(players, r)
+	for i := 0; i < 10; i++ {
+		g.Play()
+	}
+
+	for spec, want := range map[string]string{
+		"nn":                           "bots: nn: no weights file",
+		"nn:weights=" + name + ".json": "no such file",
+	} {
+		specs, _ := bots.Parse(spec)
+		if _, err := specs.New(r, 0); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("New(%v): error %v, expected %v", spec, err, want)
+		}
+	}
+	if _, err := specs.New(game.Newmarket(), 0); err == nil || !strings.Contains(err.Error(), "inputs") {
+		t.Errorf("New under other Rules: error %v", err)
+	}
+}
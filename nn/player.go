@@ -0,0 +1,61 @@
+package nn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/env"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/playerutil"
+)
+
+// A Player is a game.Player that leads from the major suit when its Network
+// favors it.
+type Player struct {
+	net   *Network
+	rules game.Rules
+
+	playerutil.Hand
+	obs []float32
+}
+
+// NewPlayer returns a Player that evaluates the given Network in games played
+// according to the given Rules. NewPlayer panics if the Network's inputs do
+// not match the observations of package env under the Rules.
+func NewPlayer(net *Network, rules game.Rules) *Player {
+	if n := env.Size(rules); net.Inputs() != n {
+		panic(fmt.Sprintf("nn: network has %v inputs, expected %v", net.Inputs(), n))
+	}
+	return &Player{net: net, rules: rules, obs: make([]float32, net.Inputs())}
+}
+
+// PlayMajor implements game.Player.
+func (p *Player) PlayMajor(t game.TableState, color card.Color) bool {
+	return p.net.Major(env.Observe(p.obs, p.rules, p.Pos, p.Cards, t, color, true))
+}
+
+func init() {
+	bots.Register(bots.Bot{
+		Name: "nn",
+		Help: "leads according to a neural network trained on simulated hands",
+		Options: []bots.Option{
+			{Name: "weights", Kind: bots.String, Help: "a JSON or binary file holding the network"},
+		},
+		New: func(opts bots.Options) (game.Player, error) {
+			name := opts.String("weights")
+			if name == "" {
+				return nil, errors.New("no weights file")
+			}
+			net, err := Load(name)
+			if err != nil {
+				return nil, err
+			}
+			if n := env.Size(opts.Rules); net.Inputs() != n {
+				return nil, fmt.Errorf("%v: network has %v inputs, expected %v", name, net.Inputs(), n)
+			}
+			return NewPlayer(net, opts.Rules), nil
+		},
+	})
+}
@@ -0,0 +1,384 @@
+package nn
+
+import (
+	"encoding/gob"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/env"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Sample records a decision between the minor and major suit, as observed by
+// the player who made it.
+type Sample struct {
+	// Obs is the observation, as encoded by env.Observe.
+	Obs []float32
+
+	// Gain is the mean number of chips that the player gained from the
+	// decision to the end of the hand after leading from the minor suit and
+	// after leading from the major suit.
+	Gain [2]float32
+}
+
+// A Config configures the simulation of hands by Generate.
+type Config struct {
+	Rules   game.Rules
+	Players int
+
+	// Hands is the number of hands to simulate. They are played in games of
+	// handsPerGame hands each, so that the stakes vary as they do in play.
+	Hands int
+
+	// Rollouts is the number of times that each decision's hand is played
+	// out after each choice to estimate its Gain, or if it is not positive, 1.
+	Rollouts int
+
+	// Policy decides for every player in the hands and their rollouts.
+	// If it is nil, every player leads from either suit at random.
+	Policy *Network
+
+	Seed int64
+
+	// Workers is the number of goroutines that simulate hands, or if it is
+	// not positive, the number of CPUs that may run Go code at once.
+	Workers int
+}
+
+// handsPerGame is the number of hands of each game that Generate plays.
+const handsPerGame = 10
+
+// Generate simulates hands and returns a Sample of each decision between the
+// minor and major suit. The Samples depend only on the Config, and not on the
+// number of Workers.
+func Generate(cfg Config) []Sample {
+	if cfg.Rollouts <= 0 {
+		cfg.Rollouts = 1
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	games := make([][]Sample, (cfg.Hands+handsPerGame-1)/handsPerGame)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hands := cfg.Hands - i*handsPerGame
+				if hands > handsPerGame {
+					hands = handsPerGame
+				}
+				games[i] = simulate(cfg, cfg.Seed+int64(i), hands)
+			}
+		}()
+	}
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	var samples []Sample
+	for _, s := range games {
+		samples = append(samples, s...)
+	}
+	return samples
+}
+
+// seat is a Player that occupies a seat in the games of Generate, whose
+// decisions are made by a simulator. Its index distinguishes the seats, since
+// the Players of a Game must be distinct.
+type seat struct{ index int }
+
+func (*seat) Init(int, int, []card.Card, []int, int) {}
+
+func (*seat) Note(int, card.Card) {}
+
+func (*seat) PlayMajor(game.TableState, card.Color) bool { return false }
+
+// A simulator plays the hands of a game of Generate.
+type simulator struct {
+	cfg Config
+	rng *rand.Rand
+	obs []float32
+}
+
+// simulate plays the given number of hands of a game seeded with the given
+// value and returns their Samples.
+func simulate(cfg Config, seed int64, hands int) []Sample {
+	players := make([]game.Player, cfg.Players)
+	for i := range players {
+		players[i] = &seat{i}
+	}
+	g := game.New(players, cfg.Rules)
+	g.Seed(seed)
+	s := &simulator{
+		cfg: cfg,
+		rng: rand.New(rand.NewSource(seed)),
+		obs: make([]float32, env.Size(cfg.Rules)),
+	}
+	var samples []Sample
+	for i := 0; i < hands; i++ {
+		h := g.Begin()
+		for {
+			e, ok := h.Next()
+			if !ok {
+				break
+			}
+			if e.Kind != game.DecisionEvent {
+				continue
+			}
+			if color, ok := same(e.Suits); ok {
+				sample := Sample{Obs: append([]float32(nil), s.observe(h, e.Pos, color)...)}
+				score := h.State().Score[e.Pos]
+				for a := range sample.Gain {
+					var sum int
+					for r := 0; r < cfg.Rollouts; r++ {
+						c := h.Clone(nil)
+						c.Decide(action(color, a == 1))
+						sum += s.rollout(c, e.Pos) - score
+					}
+					sample.Gain[a] = float32(sum) / float32(cfg.Rollouts)
+				}
+				samples = append(samples, sample)
+			}
+			h.Decide(s.decide(h, e))
+		}
+	}
+	return samples
+}
+
+// rollout plays a hand out and returns the final score of the player at
+// position pos.
+func (s *simulator) rollout(h *game.Hand, pos int) int {
+	for {
+		e, ok := h.Next()
+		if !ok {
+			return h.State().Score[pos]
+		}
+		if e.Kind == game.DecisionEvent {
+			h.Decide(s.decide(h, e))
+		}
+	}
+}
+
+// decide returns the suit from which the player who must make a decision
+// leads according to the Policy.
+func (s *simulator) decide(h *game.Hand, e game.Event) card.Suit {
+	color, ok := same(e.Suits)
+	switch {
+	case !ok:
+		return e.Suits[s.rng.Intn(len(e.Suits))]
+	case s.cfg.Policy == nil:
+		return action(color, s.rng.Intn(2) == 1)
+	default:
+		return action(color, s.cfg.Policy.Major(s.observe(h, e.Pos, color)))
+	}
+}
+
+// observe returns the observation of the player at position pos of a decision
+// in the given color. The returned slice is reused by the next call.
+func (s *simulator) observe(h *game.Hand, pos int, color card.Color) []float32 {
+	return env.Observe(s.obs, s.cfg.Rules, pos, h.Cards(pos), h.State(), color, true)
+}
+
+// same reports whether the suits are the minor and major suit of a color, and
+// returns the color.
+func same(suits []card.Suit) (card.Color, bool) {
+	if len(suits) != 2 || suits[0].Color() != suits[1].Color() {
+		return 0, false
+	}
+	return suits[0].Color(), true
+}
+
+// action returns the suit of a color from which to lead.
+func action(color card.Color, major bool) card.Suit {
+	if major {
+		return color.Major()
+	}
+	return color.Minor()
+}
+
+// WriteSamples encodes Samples to w, to be decoded by ReadSamples.
+func WriteSamples(w io.Writer, samples []Sample) error {
+	return gob.NewEncoder(w).Encode(samples)
+}
+
+// ReadSamples decodes Samples encoded by WriteSamples.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	err := gob.NewDecoder(r).Decode(&samples)
+	return samples, err
+}
+
+// A Trainer fits a Network to Samples by minibatch gradient descent with the
+// Adam optimizer. The Network learns to classify the better choice of each
+// Sample, weighted by the difference between the Gains of the choices.
+type Trainer struct {
+	net *Network
+	rng *rand.Rand
+
+	// Rate is the step size, and Batch the number of Samples of each step.
+	Rate  float64
+	Batch int
+
+	// grad, m and v hold the gradient and Adam's moment estimates for the
+	// Weights and then the Biases of each Layer.
+	grad, m, v [][]float32
+	t          int
+
+	// acts holds the input and the outputs of each Layer for one Sample,
+	// and delta the gradient of the loss with respect to them.
+	acts, delta [][]float32
+}
+
+// NewTrainer returns a Trainer that trains the given Network, drawing the order
+// of the Samples from a random number generator seeded with the given value.
+func NewTrainer(net *Network, seed int64) *Trainer {
+	t := &Trainer{
+		net:   net,
+		rng:   rand.New(rand.NewSource(seed)),
+		Rate:  1e-3,
+		Batch: 64,
+		acts:  [][]float32{make([]float32, net.Inputs())},
+		delta: [][]float32{make([]float32, net.Inputs())},
+	}
+	for _, l := range net.Layers {
+		for _, n := range []int{len(l.Weights), len(l.Biases)} {
+			t.grad = append(t.grad, make([]float32, n))
+			t.m = append(t.m, make([]float32, n))
+			t.v = append(t.v, make([]float32, n))
+		}
+		t.acts = append(t.acts, make([]float32, l.Out))
+		t.delta = append(t.delta, make([]float32, l.Out))
+	}
+	return t
+}
+
+// Epoch trains on each Sample once, in a random order, and returns the mean
+// weighted loss, or 0 if there are no Samples.
+func (t *Trainer) Epoch(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	order := t.rng.Perm(len(samples))
+	var loss float64
+	for start := 0; start < len(order); start += t.Batch {
+		end := start + t.Batch
+		if end > len(order) {
+			end = len(order)
+		}
+		for _, g := range t.grad {
+			for i := range g {
+				g[i] = 0
+			}
+		}
+		for _, i := range order[start:end] {
+			loss += t.backward(samples[i], float32(end-start))
+		}
+		t.step()
+	}
+	return loss / float64(len(samples))
+}
+
+// backward adds to the gradient that of the loss of a Sample, divided by n,
+// and returns the loss.
+func (t *Trainer) backward(s Sample, n float32) float64 {
+	layers := t.net.Layers
+	copy(t.acts[0], s.Obs)
+	for i := range layers {
+		layers[i].apply(t.acts[i], t.acts[i+1], i < len(layers)-1)
+	}
+	z := float64(t.acts[len(layers)][0])
+	weight, label := math.Abs(float64(s.Gain[1]-s.Gain[0])), 0.0
+	if s.Gain[1] > s.Gain[0] {
+		label = 1
+	}
+	// The loss is the weighted cross entropy of the label and the sigmoid of
+	// the logit z, computed stably.
+	loss := weight * (math.Max(z, 0) - z*label + math.Log1p(math.Exp(-math.Abs(z))))
+	t.delta[len(layers)][0] = float32(weight*(1/(1+math.Exp(-z))-label)) / n
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := &layers[i]
+		in, out, dIn := t.acts[i], t.delta[i+1], t.delta[i]
+		gw, gb := t.grad[2*i], t.grad[2*i+1]
+		for j := range dIn {
+			dIn[j] = 0
+		}
+		for o, d := range out {
+			if d == 0 {
+				continue
+			}
+			gb[o] += d
+			w := l.Weights[o*l.In : (o+1)*l.In]
+			g := gw[o*l.In : (o+1)*l.In]
+			for j, x := range in {
+				g[j] += d * x
+				dIn[j] += d * w[j]
+			}
+		}
+		if i > 0 {
+			// The derivative of the activation is 0 where it is 0.
+			for j, x := range in {
+				if x == 0 {
+					dIn[j] = 0
+				}
+			}
+		}
+	}
+	return loss
+}
+
+// Adam's decay rates for its moment estimates, and its guard against division
+// by zero.
+const (
+	beta1   = 0.9
+	beta2   = 0.999
+	epsilon = 1e-8
+)
+
+// step updates the Network's parameters from the gradient.
+func (t *Trainer) step() {
+	t.t++
+	c1 := 1 - math.Pow(beta1, float64(t.t))
+	c2 := 1 - math.Pow(beta2, float64(t.t))
+	for k, g := range t.grad {
+		var params []float32
+		if l := &t.net.Layers[k/2]; k%2 == 0 {
+			params = l.Weights
+		} else {
+			params = l.Biases
+		}
+		m, v := t.m[k], t.v[k]
+		for i, gi := range g {
+			m[i] = beta1*m[i] + (1-beta1)*gi
+			v[i] = beta2*v[i] + (1-beta2)*gi*gi
+			mhat, vhat := float64(m[i])/c1, float64(v[i])/c2
+			params[i] -= float32(t.Rate * mhat / (math.Sqrt(vhat) + epsilon))
+		}
+	}
+}
+
+// Regret returns the mean number of chips per Sample that a Network loses by
+// its choices relative to the better choice of each Sample.
+func Regret(net *Network, samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range samples {
+		best := math.Max(float64(s.Gain[0]), float64(s.Gain[1]))
+		a := 0
+		if net.Major(s.Obs) {
+			a = 1
+		}
+		sum += best - float64(s.Gain[a])
+	}
+	return sum / float64(len(samples))
+}
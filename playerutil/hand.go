@@ -0,0 +1,33 @@
+package playerutil
+
+import "github.com/dkmccandless/tripoli/card"
+
+// A Hand tracks the cards held by a Player. A Player that embeds a Hand gets
+// the Init and Note methods of game.Player: Init records the Player's position
+// and a copy of the cards dealt to it, and Note removes each card that the
+// Player plays.
+type Hand struct {
+	// Pos is the Player's position in the hand, counting from the dealer's
+	// left, and Cards the cards that it holds, in the order dealt.
+	Pos   int
+	Cards []card.Card
+}
+
+// Init implements game.Player.
+func (h *Hand) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	h.Pos = pos
+	h.Cards = append(h.Cards[:0], hand...)
+}
+
+// Note implements game.Player.
+func (h *Hand) Note(pos int, c card.Card) {
+	if pos != h.Pos {
+		return
+	}
+	for i, d := range h.Cards {
+		if d == c {
+			h.Cards = append(h.Cards[:i], h.Cards[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package playerutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+)
+
+func TestHand(t *testing.T) {
+	var h Hand
+	dealt := []card.Card{3, 4, 17, 40}
+	h.Init(3, 1, dealt, nil, 0)
+	dealt[0] = 0
+	h.Note(0, 4)
+	h.Note(1, 17)
+	h.Note(1, 3)
+	if want := (Hand{Pos: 1, Cards: []card.Card{4, 40}}); !reflect.DeepEqual(h, want) {
+		t.Errorf("Hand is %+v, expected %+v", h, want)
+	}
+	h.Init(3, 0, []card.Card{5}, nil, 0)
+	if want := (Hand{Pos: 0, Cards: []card.Card{5}}); !reflect.DeepEqual(h, want) {
+		t.Errorf("Hand after a second Init is %+v, expected %+v", h, want)
+	}
+}
@@ -0,0 +1,227 @@
+// Package playerutil provides decorators of Players: Players that forward each
+// call to an inner Player, and log, record, time or guard it along the way.
+// Decorators compose, as in
+//
+//	p = playerutil.Log(playerutil.Recover(playerutil.Time(p, h), report), logger)
+//
+// A decorated Player is a game.Listener, which forwards events to the inner
+// Player if it is one, and is a game.SuitChooser exactly when the inner Player
+// is, so that the engine treats it as it treats the inner Player.
+//
+// The package also provides Hand, which Players embed to track the cards they
+// hold.
+package playerutil
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A decorator implements every method that the engine may call.
+type decorator interface {
+	game.Listener
+	ChooseSuit(t game.TableState, suits []card.Suit) card.Suit
+}
+
+// listener hides the ChooseSuit method of a decorator.
+type listener struct{ game.Listener }
+
+// finish returns a decorator of an inner Player, which is a SuitChooser only
+// if the inner Player is.
+func finish(d decorator, inner game.Player) game.Player {
+	if _, ok := inner.(game.SuitChooser); ok {
+		return d
+	}
+	return listener{d}
+}
+
+// base forwards every call to an inner Player. Decorators embed it and
+// override the methods that they intercept.
+type base struct {
+	inner game.Player
+}
+
+func (b base) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	b.inner.Init(n, pos, hand, stake, kitty)
+}
+
+func (b base) Note(pos int, c card.Card) { b.inner.Note(pos, c) }
+
+func (b base) PlayMajor(t game.TableState, color card.Color) bool {
+	return b.inner.PlayMajor(t, color)
+}
+
+func (b base) ChooseSuit(t game.TableState, suits []card.Suit) card.Suit {
+	return b.inner.(game.SuitChooser).ChooseSuit(t, suits)
+}
+
+func (b base) Pass(pos int, suits []card.Suit) {
+	if l, ok := b.inner.(game.Listener); ok {
+		l.Pass(pos, suits)
+	}
+}
+
+func (b base) PayKitty(pos, n int) {
+	if l, ok := b.inner.(game.Listener); ok {
+		l.PayKitty(pos, n)
+	}
+}
+
+func (b base) Collect(pos int, pot string, n int) {
+	if l, ok := b.inner.(game.Listener); ok {
+		l.Collect(pos, pot, n)
+	}
+}
+
+func (b base) HandEnd(res game.Result) {
+	if l, ok := b.inner.(game.Listener); ok {
+		l.HandEnd(res)
+	}
+}
+
+// Log returns a Player that logs each call to p and each decision that p
+// makes to l.
+func Log(p game.Player, l *log.Logger) game.Player {
+	return finish(&logger{base{p}, l}, p)
+}
+
+type logger struct {
+	base
+	l *log.Logger
+}
+
+func (lg logger) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	lg.l.Printf("Init(%v, %v, %v, %v, %v)", n, pos, cards(hand), stake, kitty)
+	lg.base.Init(n, pos, hand, stake, kitty)
+}
+
+func (lg logger) Note(pos int, c card.Card) {
+	lg.l.Printf("Note(%v, %v)", pos, cards([]card.Card{c}))
+	lg.base.Note(pos, c)
+}
+
+func (lg logger) PlayMajor(t game.TableState, color card.Color) bool {
+	major := lg.base.PlayMajor(t, color)
+	lg.l.Printf("PlayMajor(%v) = %v", color, major)
+	return major
+}
+
+func (lg logger) ChooseSuit(t game.TableState, suits []card.Suit) card.Suit {
+	s := lg.base.ChooseSuit(t, suits)
+	lg.l.Printf("ChooseSuit(%v) = %v", suits, s)
+	return s
+}
+
+func (lg logger) Pass(pos int, suits []card.Suit) {
+	lg.l.Printf("Pass(%v, %v)", pos, suits)
+	lg.base.Pass(pos, suits)
+}
+
+func (lg logger) PayKitty(pos, n int) {
+	lg.l.Printf("PayKitty(%v, %v)", pos, n)
+	lg.base.PayKitty(pos, n)
+}
+
+func (lg logger) Collect(pos int, pot string, n int) {
+	lg.l.Printf("Collect(%v, %v, %v)", pos, pot, n)
+	lg.base.Collect(pos, pot, n)
+}
+
+func (lg logger) HandEnd(res game.Result) {
+	lg.l.Printf("HandEnd(winner %v, delta %v)", res.Winner, res.Delta)
+	lg.base.HandEnd(res)
+}
+
+// cards formats cards by rank and suit.
+func cards(cs []card.Card) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = fmt.Sprintf("%v of %v", c.Rank(), c.Suit())
+	}
+	if len(cs) == 1 {
+		return s[0]
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// A PanicError records a panic recovered by a Player returned by Recover.
+type PanicError struct {
+	// Method is the name of the method that panicked.
+	Method string
+
+	// Value is the value passed to panic, and Stack the stack of the
+	// goroutine at the time.
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("playerutil: %v panicked: %v", e.Method, e.Value)
+}
+
+// Recover returns a Player that recovers from any panic in a call to p,
+// reports it to report if report is not nil, and carries on. When p panics
+// while deciding, the Player leads from the minor suit, or from the first of
+// the suits offered to ChooseSuit.
+func Recover(p game.Player, report func(err *PanicError)) game.Player {
+	return finish(&recoverer{base{p}, report}, p)
+}
+
+type recoverer struct {
+	base
+	report func(err *PanicError)
+}
+
+// recover recovers from a panic in the named method, if any, and reports it.
+// It must be deferred directly.
+func (r recoverer) recover(method string) {
+	if v := recover(); v != nil && r.report != nil {
+		r.report(&PanicError{Method: method, Value: v, Stack: debug.Stack()})
+	}
+}
+
+func (r recoverer) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	defer r.recover("Init")
+	r.base.Init(n, pos, hand, stake, kitty)
+}
+
+func (r recoverer) Note(pos int, c card.Card) {
+	defer r.recover("Note")
+	r.base.Note(pos, c)
+}
+
+func (r recoverer) PlayMajor(t game.TableState, color card.Color) (major bool) {
+	defer r.recover("PlayMajor")
+	return r.base.PlayMajor(t, color)
+}
+
+func (r recoverer) ChooseSuit(t game.TableState, suits []card.Suit) (s card.Suit) {
+	s = suits[0]
+	defer r.recover("ChooseSuit")
+	return r.base.ChooseSuit(t, suits)
+}
+
+func (r recoverer) Pass(pos int, suits []card.Suit) {
+	defer r.recover("Pass")
+	r.base.Pass(pos, suits)
+}
+
+func (r recoverer) PayKitty(pos, n int) {
+	defer r.recover("PayKitty")
+	r.base.PayKitty(pos, n)
+}
+
+func (r recoverer) Collect(pos int, pot string, n int) {
+	defer r.recover("Collect")
+	r.base.Collect(pos, pot, n)
+}
+
+func (r recoverer) HandEnd(res game.Result) {
+	defer r.recover("HandEnd")
+	r.base.HandEnd(res)
+}
@@ -0,0 +1,169 @@
+package playerutil
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// alternator is a Player that alternates between the minor and major suits.
+type alternator struct{ n int }
+
+func (*alternator) Init(int, int, []card.Card, []int, int) {}
+
+func (*alternator) Note(int, card.Card) {}
+
+func (a *alternator) PlayMajor(game.TableState, card.Color) bool {
+	a.n++
+	return a.n%2 == 0
+}
+
+// chooser is a SuitChooser that always chooses the last suit offered.
+type chooser struct{ alternator }
+
+func (*chooser) ChooseSuit(t game.TableState, suits []card.Suit) card.Suit {
+	return suits[len(suits)-1]
+}
+
+// panicker is a SuitChooser that panics whenever it is asked to decide.
+type panicker struct{ alternator }
+
+func (*panicker) PlayMajor(game.TableState, card.Color) bool { panic("PlayMajor") }
+
+func (*panicker) ChooseSuit(game.TableState, []card.Suit) card.Suit { panic("ChooseSuit") }
+
+// play plays hands of a seeded game according to Pope Joan, which allows play
+// to restart in any suit, and returns the scores.
+func play(players []game.Player) []int {
+	g := game.New(players, game.PopeJoan())
+	g.Seed(1)
+	for i := 0; i < 20; i++ {
+		g.Play()
+	}
+	scores := make([]int, len(players))
+	for i, p := range players {
+		scores[i] = g.Score()[p]
+	}
+	return scores
+}
+
+// decorate applies every decorator to p.
+func decorate(p game.Player, rec *Recorder, h *Histogram, l *log.Logger, report func(*PanicError)) game.Player {
+	return Log(Recover(Record(Time(p, h), rec), report), l)
+}
+
+func TestInterfaces(t *testing.T) {
+	var rec Recorder
+	var h Histogram
+	l := log.New(&bytes.Buffer{}, "", 0)
+	for name, test := range map[string]struct {
+		p       game.Player
+		chooser bool
+	}{
+		"Player":      {&alternator{}, false},
+		"SuitChooser": {&chooser{}, true},
+	} {
+		for dname, d := range map[string]game.Player{
+			"Log":     Log(test.p, l),
+			"Record":  Record(test.p, &rec),
+			"Time":    Time(test.p, &h),
+			"Recover": Recover(test.p, nil),
+			"all":     decorate(test.p, &rec, &h, l, nil),
+		} {
+			if _, ok := d.(game.SuitChooser); ok != test.chooser {
+				t.Errorf("%v(%v) is a SuitChooser: %v, expected %v", dname, name, ok, test.chooser)
+			}
+			if _, ok := d.(game.Listener); !ok {
+				t.Errorf("%v(%v) is not a Listener", dname, name)
+			}
+		}
+	}
+}
+
+func TestTransparent(t *testing.T) {
+	plain := []game.Player{&alternator{}, &chooser{}, &alternator{}, &chooser{}}
+	want := play(plain)
+
+	var logs bytes.Buffer
+	var h Histogram
+	recs := make([]Recorder, 4)
+	decorated := []game.Player{&alternator{}, &chooser{}, &alternator{}, &chooser{}}
+	for i, p := range decorated {
+		decorated[i] = decorate(p, &recs[i], &h, log.New(&logs, "", 0), nil)
+	}
+	if got := play(decorated); !reflect.DeepEqual(got, want) {
+		t.Errorf("decorated Players scored %v, expected %v", got, want)
+	}
+
+	var decisions int
+	for i, rec := range recs {
+		if len(rec.Hands) != 20 {
+			t.Fatalf("Recorder %v recorded %v hands, expected 20", i, len(rec.Hands))
+		}
+		for _, hr := range rec.Hands {
+			if hr.Result == nil || len(hr.Cards) == 0 {
+				t.Errorf("Recorder %v: incomplete HandRecord %+v", i, hr)
+			}
+			for _, d := range hr.Decisions {
+				decisions++
+				if d.State.Leader != hr.Pos {
+					t.Errorf("Recorder %v: Decision by position %v in a hand at position %v", i, d.State.Leader, hr.Pos)
+				}
+			}
+		}
+	}
+	if decisions == 0 {
+		t.Fatal("no decisions recorded")
+	}
+	if decisions != h.Count() {
+		t.Errorf("recorded %v decisions and timed %v", decisions, h.Count())
+	}
+	if n := strings.Count(logs.String(), "PlayMajor(") + strings.Count(logs.String(), "ChooseSuit("); n != decisions {
+		t.Errorf("logged %v decisions, expected %v", n, decisions)
+	}
+	if n := strings.Count(logs.String(), "HandEnd("); n != 80 {
+		t.Errorf("logged %v hand ends, expected 80", n)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var errs []*PanicError
+	report := func(err *PanicError) { errs = append(errs, err) }
+	players := []game.Player{Recover(&panicker{}, report), &alternator{}, Recover(&chooser{}, report)}
+	play(players)
+	if len(errs) == 0 {
+		t.Fatal("no panics reported")
+	}
+	for _, err := range errs {
+		if err.Method != err.Value || len(err.Stack) == 0 {
+			t.Errorf("PanicError %v: Method %v, Value %v", err, err.Method, err.Value)
+		}
+	}
+	if s := (&PanicError{Method: "Note", Value: "oops"}).Error(); s != "playerutil: Note panicked: oops" {
+		t.Errorf("Error() = %q", s)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var h Histogram
+	if h.Quantile(0.5) != 0 || h.Mean() != 0 {
+		t.Errorf("empty Histogram: Quantile %v, Mean %v", h.Quantile(0.5), h.Mean())
+	}
+	for _, d := range []time.Duration{0, 3, 5, 6, 100, 1000} {
+		h.Observe(d)
+	}
+	for q, want := range map[float64]time.Duration{0: 0, 0.3: 3, 0.5: 7, 0.8: 127, 1: 1023} {
+		if got := h.Quantile(q); got != want {
+			t.Errorf("Quantile(%v) = %v, expected %v", q, got, want)
+		}
+	}
+	if h.Count() != 6 || h.Mean() != 185 {
+		t.Errorf("Count() = %v, Mean() = %v, expected 6 and 185", h.Count(), h.Mean())
+	}
+}
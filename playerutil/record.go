@@ -0,0 +1,99 @@
+package playerutil
+
+import (
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Decision records a decision made by a Player.
+type Decision struct {
+	// State is the public state of the hand when the Player decided.
+	State game.TableState
+
+	// Suits are the suits among which the Player chose: the minor and
+	// major suits of a color, or the suits offered to ChooseSuit.
+	Suits []card.Suit
+
+	// Suit is the suit from which the Player decided to lead.
+	Suit card.Suit
+}
+
+// A HandRecord records a Player's view of a hand and the decisions that it
+// made.
+type HandRecord struct {
+	// Pos is the Player's position, and Cards the cards dealt to it.
+	Pos   int
+	Cards []card.Card
+
+	Decisions []Decision
+
+	// Result is the Result of the hand, once it is over.
+	Result *game.Result
+}
+
+// A Recorder records the hands played by a Player returned by Record.
+type Recorder struct {
+	Hands []HandRecord
+}
+
+// Record returns a Player that records each hand that p plays, and each
+// decision that it makes, to rec.
+func Record(p game.Player, rec *Recorder) game.Player {
+	return finish(&recorder{base{p}, rec}, p)
+}
+
+type recorder struct {
+	base
+	rec *Recorder
+}
+
+// hand returns the record of the current hand.
+func (r recorder) hand() *HandRecord {
+	if len(r.rec.Hands) == 0 {
+		// The Player was decorated in the middle of a hand.
+		r.rec.Hands = append(r.rec.Hands, HandRecord{Pos: -1})
+	}
+	return &r.rec.Hands[len(r.rec.Hands)-1]
+}
+
+func (r recorder) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	r.rec.Hands = append(r.rec.Hands, HandRecord{Pos: pos, Cards: append([]card.Card(nil), hand...)})
+	r.base.Init(n, pos, hand, stake, kitty)
+}
+
+func (r recorder) PlayMajor(t game.TableState, color card.Color) bool {
+	major := r.base.PlayMajor(t, color)
+	s := color.Minor()
+	if major {
+		s = color.Major()
+	}
+	h := r.hand()
+	h.Decisions = append(h.Decisions, Decision{
+		State: t.Clone(),
+		Suits: []card.Suit{color.Minor(), color.Major()},
+		Suit:  s,
+	})
+	return major
+}
+
+func (r recorder) ChooseSuit(t game.TableState, suits []card.Suit) card.Suit {
+	s := r.base.ChooseSuit(t, suits)
+	h := r.hand()
+	h.Decisions = append(h.Decisions, Decision{
+		State: t.Clone(),
+		Suits: append([]card.Suit(nil), suits...),
+		Suit:  s,
+	})
+	return s
+}
+
+func (r recorder) HandEnd(res game.Result) {
+	res.Hands = append([][]card.Card(nil), res.Hands...)
+	for i := range res.Hands {
+		res.Hands[i] = append([]card.Card(nil), res.Hands[i]...)
+	}
+	res.Widow = append([]card.Card(nil), res.Widow...)
+	res.Delta = append([]int(nil), res.Delta...)
+	r.hand().Result = &res
+	r.base.HandEnd(res)
+}
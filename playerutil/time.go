@@ -0,0 +1,113 @@
+package playerutil
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Histogram records a distribution of durations in buckets whose bounds are
+// powers of two nanoseconds. It is safe for concurrent use, so that Players in
+// different games may share one.
+type Histogram struct {
+	mu sync.Mutex
+
+	// counts records the number of durations d in each bucket i, which holds
+	// those with 2^(i-1) <= d < 2^i nanoseconds, and bucket 0 those of 0.
+	counts [64]int
+	n      int
+	total  time.Duration
+}
+
+// Observe adds a duration to the Histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[bits.Len64(uint64(d))]++
+	h.n++
+	h.total += d
+}
+
+// Count returns the number of durations observed.
+func (h *Histogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.n
+}
+
+// Mean returns the mean of the durations observed, or 0 if there are none.
+func (h *Histogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.n == 0 {
+		return 0
+	}
+	return h.total / time.Duration(h.n)
+}
+
+// Quantile returns an upper bound, within a factor of two, of the qth quantile
+// of the durations observed, for q between 0 and 1. It returns 0 if there are
+// none.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.n == 0 {
+		return 0
+	}
+	rank := int(q*float64(h.n) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int
+	for i, c := range h.counts {
+		if seen += c; seen >= rank {
+			return upper(i)
+		}
+	}
+	return upper(len(h.counts) - 1)
+}
+
+// upper returns the upper bound of bucket i.
+func upper(i int) time.Duration {
+	if i == 0 {
+		return 0
+	}
+	return time.Duration(uint64(1)<<uint(i) - 1)
+}
+
+// String summarizes the Histogram.
+func (h *Histogram) String() string {
+	return fmt.Sprintf("n=%v mean=%v p50<=%v p99<=%v max<=%v",
+		h.Count(), h.Mean(), h.Quantile(0.5), h.Quantile(0.99), h.Quantile(1))
+}
+
+// Time returns a Player that records the latency of each decision that p makes
+// in h.
+func Time(p game.Player, h *Histogram) game.Player {
+	return finish(&timer{base{p}, h}, p)
+}
+
+type timer struct {
+	base
+	h *Histogram
+}
+
+func (t timer) PlayMajor(ts game.TableState, color card.Color) bool {
+	defer t.since(time.Now())
+	return t.base.PlayMajor(ts, color)
+}
+
+func (t timer) ChooseSuit(ts game.TableState, suits []card.Suit) card.Suit {
+	defer t.since(time.Now())
+	return t.base.ChooseSuit(ts, suits)
+}
+
+// since records the time elapsed since start.
+func (t timer) since(start time.Time) { t.h.Observe(time.Since(start)) }
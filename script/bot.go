@@ -0,0 +1,34 @@
+package script
+
+import (
+	"errors"
+	"os"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func init() {
+	bots.Register(bots.Bot{
+		Name: "script",
+		Help: "leads according to the rules of a script",
+		Options: []bots.Option{
+			{Name: "file", Kind: bots.String, Help: "the script file"},
+		},
+		New: func(opts bots.Options) (game.Player, error) {
+			name := opts.String("file")
+			if name == "" {
+				return nil, errors.New("no script file")
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			s, err := Parse(data)
+			if err != nil {
+				return nil, err
+			}
+			return NewPlayer(s, opts.Rules), nil
+		},
+	})
+}
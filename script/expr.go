@@ -0,0 +1,288 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// An expr is a compiled expression, which evaluates to an integer.
+type expr func(f *features) int
+
+// A token is a lexical token of an expression: a number, a name, or an
+// operator or parenthesis. col is its column, counting from 1.
+type token struct {
+	text string
+	col  int
+}
+
+// lex splits an expression into tokens.
+func lex(src string) ([]token, *Error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case isName(c) || unicode.IsDigit(c):
+			for i < len(src) && (isName(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+		case strings.ContainsRune("<>=!", c):
+			i++
+			if i < len(src) && src[i] == '=' {
+				i++
+			}
+		case strings.ContainsRune("+-*()", c):
+			i++
+		default:
+			return nil, &Error{Column: i + 1, Msg: fmt.Sprintf("unexpected %q", c)}
+		}
+		toks = append(toks, token{src[start:i], start + 1})
+	}
+	return toks, nil
+}
+
+func isName(c rune) bool { return c == '_' || unicode.IsLetter(c) }
+
+// names maps the name of each feature to a function that returns its value.
+var names = map[string]expr{
+	"cards":   func(f *features) int { return f.cards },
+	"short":   func(f *features) int { return f.short },
+	"players": func(f *features) int { return f.players },
+	"kitty":   func(f *features) int { return f.kitty },
+	"red":     func(f *features) int { return f.red },
+	"true":    func(*features) int { return 1 },
+	"false":   func(*features) int { return 0 },
+}
+
+func init() {
+	for i, prefix := range []string{"minor.", "major."} {
+		i := i
+		for name, e := range map[string]expr{
+			"len":     func(f *features) int { return f.suit[i].len },
+			"low":     func(f *features) int { return f.suit[i].low },
+			"high":    func(f *features) int { return f.suit[i].high },
+			"run":     func(f *features) int { return f.suit[i].run },
+			"counter": func(f *features) int { return f.suit[i].counter },
+			"stake":   func(f *features) int { return f.suit[i].stake },
+			"played":  func(f *features) int { return f.suit[i].played },
+		} {
+			names[prefix+name] = e
+		}
+	}
+}
+
+// compile compiles an expression.
+func compile(src string) (expr, *Error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, end: len(src) + 1}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.toks) {
+		return nil, p.errorf("unexpected %q", p.toks[p.i].text)
+	}
+	return e, nil
+}
+
+// A parser compiles a sequence of tokens by recursive descent. Operators bind
+// from loosest to tightest as or, and, not, comparisons, addition and
+// subtraction, multiplication, and negation.
+type parser struct {
+	toks []token
+	i    int
+
+	// end is the column just past the end of the expression.
+	end int
+}
+
+// errorf returns an Error at the column of the current token.
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	col := p.end
+	if p.i < len(p.toks) {
+		col = p.toks[p.i].col
+	}
+	return &Error{Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// accept consumes the current token and returns true if it is one of the
+// given texts.
+func (p *parser) accept(texts ...string) (string, bool) {
+	if p.i == len(p.toks) {
+		return "", false
+	}
+	for _, t := range texts {
+		if p.toks[p.i].text == t {
+			p.i++
+			return t, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) or() (expr, *Error) {
+	x, err := p.and()
+	for err == nil {
+		if _, ok := p.accept("or"); !ok {
+			break
+		}
+		var y expr
+		if y, err = p.and(); err == nil {
+			x = func(x, y expr) expr {
+				return func(f *features) int { return truth(x(f) != 0 || y(f) != 0) }
+			}(x, y)
+		}
+	}
+	return x, err
+}
+
+func (p *parser) and() (expr, *Error) {
+	x, err := p.not()
+	for err == nil {
+		if _, ok := p.accept("and"); !ok {
+			break
+		}
+		var y expr
+		if y, err = p.not(); err == nil {
+			x = func(x, y expr) expr {
+				return func(f *features) int { return truth(x(f) != 0 && y(f) != 0) }
+			}(x, y)
+		}
+	}
+	return x, err
+}
+
+func (p *parser) not() (expr, *Error) {
+	if _, ok := p.accept("not"); ok {
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(f *features) int { return truth(x(f) == 0) }, nil
+	}
+	return p.cmp()
+}
+
+func (p *parser) cmp() (expr, *Error) {
+	x, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		if p.i < len(p.toks) && p.toks[p.i].text == "=" {
+			return nil, p.errorf("unexpected %q; use \"==\" to compare", "=")
+		}
+		return x, nil
+	}
+	y, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	cmp := map[string]func(a, b int) bool{
+		"<":  func(a, b int) bool { return a < b },
+		"<=": func(a, b int) bool { return a <= b },
+		">":  func(a, b int) bool { return a > b },
+		">=": func(a, b int) bool { return a >= b },
+		"==": func(a, b int) bool { return a == b },
+		"!=": func(a, b int) bool { return a != b },
+	}[op]
+	return func(f *features) int { return truth(cmp(x(f), y(f))) }, nil
+}
+
+func (p *parser) sum() (expr, *Error) {
+	x, err := p.product()
+	for err == nil {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			break
+		}
+		var y expr
+		if y, err = p.product(); err == nil {
+			x = func(x, y expr, neg bool) expr {
+				if neg {
+					return func(f *features) int { return x(f) - y(f) }
+				}
+				return func(f *features) int { return x(f) + y(f) }
+			}(x, y, op == "-")
+		}
+	}
+	return x, err
+}
+
+func (p *parser) product() (expr, *Error) {
+	x, err := p.unary()
+	for err == nil {
+		if _, ok := p.accept("*"); !ok {
+			break
+		}
+		var y expr
+		if y, err = p.unary(); err == nil {
+			x = func(x, y expr) expr {
+				return func(f *features) int { return x(f) * y(f) }
+			}(x, y)
+		}
+	}
+	return x, err
+}
+
+func (p *parser) unary() (expr, *Error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(f *features) int { return -x(f) }, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, *Error) {
+	if p.i == len(p.toks) {
+		return nil, p.errorf("unexpected end of condition")
+	}
+	t := p.toks[p.i]
+	switch {
+	case t.text == "(":
+		p.i++
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, p.errorf("missing \")\"")
+		}
+		return x, nil
+	case unicode.IsDigit(rune(t.text[0])):
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, p.errorf("malformed number %q", t.text)
+		}
+		p.i++
+		return func(*features) int { return n }, nil
+	case isName(rune(t.text[0])):
+		e, ok := names[t.text]
+		if !ok {
+			return nil, p.errorf("unknown feature %q", t.text)
+		}
+		p.i++
+		return e, nil
+	}
+	return nil, p.errorf("unexpected %q", t.text)
+}
+
+// truth returns 1 if b is true and 0 otherwise.
+func truth(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,253 @@
+// Package script provides a Player whose lead decisions are given by rules
+// written in a small expression language, so that strategies can be written
+// without writing Go.
+//
+// A script is a JSON object with an optional name and a list of rules:
+//
+//	{
+//		"name": "counters first",
+//		"rules": [
+//			{"if": "major.counter and major.stake > 0", "lead": "major"},
+//			{"if": "major.run > minor.run", "lead": "major"},
+//			{"lead": "minor"}
+//		]
+//	}
+//
+// When the Player must lead from the minor or major suit of a color, the rules
+// are considered in order, and the first whose condition holds determines the
+// lead, which is "minor" or "major". A rule without a condition always holds.
+// If no rule holds, the Player leads from the minor suit.
+//
+// A condition is an expression over integers. The features of the suits are
+// prefixed with "minor." or "major.":
+//
+//	len      the number of cards held in the suit
+//	low      the rank of the lowest card held in the suit, from 2 to 14 for an
+//	         ace, or 0 if none is held
+//	high     the rank of the highest card held in the suit, or 0
+//	run      the number of consecutive ranks held beginning with the lowest
+//	counter  1 if a card held in the suit collects a Pot, and otherwise 0
+//	stake    the number of chips in the Pots collected by the cards held in
+//	         the suit
+//	played   the number of cards of the suit played in the hand
+//
+// and the features of the hand are:
+//
+//	cards    the number of cards in the Player's hand
+//	short    the fewest cards held by any other player
+//	players  the number of players
+//	kitty    the number of chips in the Kitty
+//	red      1 if the decision is between the red suits, and otherwise 0
+//
+// Expressions combine features and integer constants with the arithmetic
+// operators +, - and *, the comparisons <, <=, >, >=, == and !=, which are 1
+// if they hold and otherwise 0, and the logical operators not, and and or,
+// which treat any nonzero value as true. The constants true and false are 1
+// and 0. Parentheses group.
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/playerutil"
+)
+
+// A Script is a compiled set of rules for the lead decision.
+type Script struct {
+	// Name is the name given in the script, if any.
+	Name string
+
+	rules []rule
+}
+
+// A rule leads from the major suit if major is true and cond holds.
+// A nil cond always holds.
+type rule struct {
+	cond  expr
+	major bool
+}
+
+// An Error describes a problem with a script.
+type Error struct {
+	// Rule is the number of the rule, counting from 1, or 0 if the problem
+	// is not with a particular rule.
+	Rule int
+
+	// Column is the column of the rule's condition, counting from 1, at
+	// which the problem lies, or 0 if the problem is not with the condition.
+	Column int
+
+	Msg string
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Rule == 0:
+		return "script: " + e.Msg
+	case e.Column == 0:
+		return fmt.Sprintf("script: rule %v: %v", e.Rule, e.Msg)
+	}
+	return fmt.Sprintf("script: rule %v, column %v: %v", e.Rule, e.Column, e.Msg)
+}
+
+// Parse compiles a script. If the script is malformed, the error is an *Error.
+func Parse(data []byte) (*Script, error) {
+	var file struct {
+		Name  string
+		Rules []json.RawMessage
+	}
+	if err := decode(data, &file); err != nil {
+		return nil, &Error{Msg: err.Error()}
+	}
+	if len(file.Rules) == 0 {
+		return nil, &Error{Msg: "no rules"}
+	}
+	s := &Script{Name: file.Name, rules: make([]rule, len(file.Rules))}
+	for i, data := range file.Rules {
+		var r struct {
+			If   string
+			Lead string
+		}
+		if err := decode(data, &r); err != nil {
+			return nil, &Error{Rule: i + 1, Msg: err.Error()}
+		}
+		switch r.Lead {
+		case "minor":
+		case "major":
+			s.rules[i].major = true
+		default:
+			return nil, &Error{Rule: i + 1, Msg: fmt.Sprintf("lead is %q, expected \"minor\" or \"major\"", r.Lead)}
+		}
+		if r.If == "" {
+			continue
+		}
+		cond, err := compile(r.If)
+		if err != nil {
+			err.Rule = i + 1
+			return nil, err
+		}
+		s.rules[i].cond = cond
+	}
+	return s, nil
+}
+
+// decode decodes JSON into v as json.Unmarshal does, but also rejects any key
+// that v has no field for, so that a misspelled key is not silently ignored.
+func decode(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// PlayMajor reports whether a player who holds the given cards leads from the
+// major suit of the given color, in a game played according to the given
+// Rules.
+func (s *Script) PlayMajor(rules game.Rules, hand []card.Card, t game.TableState, color card.Color) bool {
+	f := newFeatures(rules, hand, t, color)
+	for _, r := range s.rules {
+		if r.cond == nil || r.cond(f) != 0 {
+			return r.major
+		}
+	}
+	return false
+}
+
+// features records the values of the features of a decision.
+type features struct {
+	suit [2]struct {
+		len, low, high, run, counter, stake, played int
+	}
+	cards, short, players, kitty, red int
+}
+
+func newFeatures(rules game.Rules, hand []card.Card, t game.TableState, color card.Color) *features {
+	f := &features{
+		cards:   len(hand),
+		players: len(t.Count),
+		kitty:   t.Kitty,
+	}
+	if color == card.Red {
+		f.red = 1
+	}
+	// pots records the number of Pots that each card collects, and stake
+	// the number of chips in them.
+	var pots, stake [52]int
+	for i, p := range rules.Layout {
+		for _, c := range p.Cards {
+			if p.Trump {
+				c = t.Trump.Rank(c.Rank())
+			}
+			pots[c]++
+			if i < len(t.Stake) {
+				stake[c] += t.Stake[i]
+			}
+		}
+	}
+	for i, s := range [2]card.Suit{color.Minor(), color.Major()} {
+		sf := &f.suit[i]
+		var held [13]bool
+		for _, c := range hand {
+			if c.Suit() != s {
+				continue
+			}
+			held[c.Rank()] = true
+			sf.len++
+			sf.stake += stake[c]
+			if pots[c] > 0 {
+				sf.counter = 1
+			}
+		}
+		for r := 12; r >= 0; r-- {
+			if held[r] {
+				sf.low = r + 2
+				if sf.high == 0 {
+					sf.high = r + 2
+				}
+			}
+		}
+		for r := sf.low - 2; sf.low > 0 && r < 13 && held[r]; r++ {
+			sf.run++
+		}
+		for _, c := range t.Played {
+			if c.Suit() == s {
+				sf.played++
+			}
+		}
+	}
+	f.short = -1
+	for pos, n := range t.Count {
+		if pos != t.Leader && (f.short < 0 || n < f.short) {
+			f.short = n
+		}
+	}
+	if f.short < 0 {
+		f.short = 0
+	}
+	return f
+}
+
+// A Player is a game.Player that decides according to a Script.
+type Player struct {
+	s     *Script
+	rules game.Rules
+
+	playerutil.Hand
+}
+
+// NewPlayer returns a Player that plays according to the given Script in
+// games played according to the given Rules.
+func NewPlayer(s *Script, rules game.Rules) *Player {
+	return &Player{s: s, rules: rules}
+}
+
+// PlayMajor implements game.Player.
+func (p *Player) PlayMajor(t game.TableState, color card.Color) bool {
+	return p.s.PlayMajor(p.rules, p.Cards, t, color)
+}
@@ -0,0 +1,158 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// hand holds the five of clubs, the two, three, four and nine of diamonds and
+// the queen and king of hearts.
+var hand = []card.Card{
+	card.Clubs.Rank(card.Five),
+	card.Diamonds.Rank(card.Two), card.Diamonds.Rank(card.Three),
+	card.Diamonds.Rank(card.Four), card.Diamonds.Rank(card.Nine),
+	card.Hearts.Rank(card.Queen), card.Hearts.Rank(card.King),
+}
+
+// state is a TableState in which the Queen holds 3 chips and the King 2.
+var state = game.TableState{
+	Stake:  []int{0, 0, 3, 2, 0},
+	Kitty:  1,
+	Count:  []int{7, 4, 6},
+	Played: []card.Card{card.Clubs.Rank(card.Two), card.Diamonds.Rank(card.Five), card.Diamonds.Rank(card.Six)},
+}
+
+func TestEval(t *testing.T) {
+	f := newFeatures(game.Michigan(), hand, state, card.Red)
+	for src, want := range map[string]int{
+		"minor.len":                         4,
+		"minor.low":                         2,
+		"minor.high":                        9,
+		"minor.run":                         3,
+		"minor.counter":                     0,
+		"minor.stake":                       0,
+		"minor.played":                      2,
+		"major.len":                         2,
+		"major.low":                         12,
+		"major.high":                        13,
+		"major.run":                         2,
+		"major.counter":                     1,
+		"major.stake":                       5,
+		"major.played":                      0,
+		"cards":                             7,
+		"short":                             4,
+		"players":                           3,
+		"kitty":                             1,
+		"red":                               1,
+		"1 + 2 * 3":                         7,
+		"(1 + 2) * 3":                       9,
+		"-minor.len - -1":                   -3,
+		"major.run > minor.run":             0,
+		"major.stake >= 5 and major.len":    1,
+		"not true or false":                 0,
+		"not (true or false)":               0,
+		"minor.len == 4 and not red == 0":   1,
+		"major.counter and major.stake > 0": 1,
+	} {
+		e, err := compile(src)
+		if err != nil {
+			t.Errorf("compile(%q): %v", src, err)
+			continue
+		}
+		if got := e(f); got != want {
+			t.Errorf("%q = %v, expected %v", src, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, test := range map[string]struct {
+		src  string
+		want string
+	}{
+		"json":     {`{"rules": [`, "script: unexpected end of JSON input"},
+		"empty":    {`{"rules": []}`, "script: no rules"},
+		"lead":     {`{"rules": [{"lead": "minor"}, {"lead": "hearts"}]}`, `script: rule 2: lead is "hearts", expected "minor" or "major"`},
+		"feature":  {`{"rules": [{"if": "major.runs > 1", "lead": "major"}]}`, `script: rule 1, column 1: unknown feature "major.runs"`},
+		"assign":   {`{"rules": [{"if": "cards = 3", "lead": "major"}]}`, `script: rule 1, column 7: unexpected "="; use "==" to compare`},
+		"paren":    {`{"rules": [{"if": "(cards > 3", "lead": "major"}]}`, `script: rule 1, column 11: missing ")"`},
+		"end":      {`{"rules": [{"if": "cards >", "lead": "major"}]}`, "script: rule 1, column 8: unexpected end of condition"},
+		"trailing": {`{"rules": [{"if": "cards 3", "lead": "major"}]}`, `script: rule 1, column 7: unexpected "3"`},
+		"char":     {`{"rules": [{"if": "cards & 3", "lead": "major"}]}`, `script: rule 1, column 7: unexpected '&'`},
+		"key":      {`{"rules": [{"lead": "minor"}, {"iff": "cards > 3", "lead": "major"}]}`, `script: rule 2: json: unknown field "iff"`},
+		"top":      {`{"name": "a", "rule": []}`, `script: json: unknown field "rule"`},
+	} {
+		_, err := Parse([]byte(test.src))
+		if err == nil || err.Error() != test.want {
+			t.Errorf("Parse(%q): error %v, expected %v", name, err, test.want)
+		}
+	}
+}
+
+func TestPlayMajor(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"name": "counters",
+		"rules": [
+			{"if": "major.counter and major.stake > 0", "lead": "major"},
+			{"if": "major.run > minor.run", "lead": "major"},
+			{"lead": "minor"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "counters" {
+		t.Errorf("Name is %q, expected %q", s.Name, "counters")
+	}
+	for name, test := range map[string]struct {
+		stake []int
+		color card.Color
+		want  bool
+	}{
+		"counter":    {[]int{0, 0, 3, 0, 0}, card.Red, true},
+		"no stake":   {[]int{3, 3, 0, 0, 3}, card.Red, false},
+		"longer run": {[]int{0, 0, 0, 0, 0}, card.Black, false},
+	} {
+		ts := state
+		ts.Stake = test.stake
+		if got := s.PlayMajor(game.Michigan(), hand, ts, test.color); got != test.want {
+			t.Errorf("PlayMajor(%q) = %v, expected %v", name, got, test.want)
+		}
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	s, err := Parse([]byte(`{"rules": [{"if": "major.run > minor.run or short <= 2", "lead": "major"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := game.Michigan()
+	g := game.New([]game.Player{NewPlayer(s, rules), NewPlayer(s, rules), NewPlayer(s, rules)}, rules)
+	for i := 0; i < 20; i++ {
+		g.Play()
+	}
+}
+
+func TestBot(t *testing.T) {
+	dir := t.TempDir()
+	good, bad := filepath.Join(dir, "good.json"), filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"rules": [{"lead": "major"}]}`), 0o644)
+	os.WriteFile(bad, []byte(`{"rules": [{"if": "major.lenn", "lead": "major"}]}`), 0o644)
+	specs, err := bots.Parse("script:file=" + good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := specs.New(game.Michigan(), 0); err != nil {
+		t.Errorf("New(%v): %v", specs, err)
+	}
+	specs, _ = bots.Parse("script:file=" + bad)
+	want := `bots: script: script: rule 1, column 1: unknown feature "major.lenn"`
+	if _, err := specs.New(game.Michigan(), 0); err == nil || err.Error() != want {
+		t.Errorf("New(%v): error %v, expected %v", specs, err, want)
+	}
+}
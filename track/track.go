@@ -0,0 +1,410 @@
+// Package track infers the locations of the cards in a hand of Tripoli from
+// one player's point of view.
+//
+// A Tracker is informed of the deal and of each card played, as a Player is,
+// and of each pass, as a Listener is. From these it deduces which cards each
+// other player and the extra hand (the widow) may hold:
+//
+//   - A card that is played is no longer held.
+//   - When a run continues past a card, or stops after it, every copy of the
+//     card that any player held has been played, so any other copies are in
+//     the widow. When a run stops, the same is true of the next card.
+//   - The player who leads a run leads the lowest card that they hold in its
+//     suit.
+//   - A player who passes holds no cards in any of the suits from which they
+//     could have led.
+//
+// Assuming that every deal consistent with these deductions is equally likely,
+// a Tracker computes the exact probability of each card's location.
+package track
+
+import (
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Tracker tracks the cards in a hand of Tripoli from one player's point of
+// view. Seats are numbered by position, counting from the dealer's left, and
+// the widow is the seat numbered by the number of players.
+type Tracker struct {
+	rules game.Rules
+
+	// n is the number of players and pos is the position of the tracking
+	// player.
+	n, pos int
+
+	// copies records the number of copies of each card in the deck, mine the
+	// number in the tracking player's hand, played the number played, and
+	// shown the number known to be in the widow.
+	copies, mine, played, shown [52]int
+
+	// count records the number of cards held by each seat.
+	count []int
+
+	// allow records as a set of bits indexed by seat the seats that may hold
+	// the copies of each card whose locations are unknown.
+	allow [52]uint16
+
+	// last is the last card played, if any has been.
+	last    card.Card
+	started bool
+
+	// prob caches the probabilities computed by Prob, by seat and card,
+	// until the Tracker learns more.
+	prob [][52]float64
+}
+
+// New returns a Tracker for a game played according to the given Rules.
+func New(rules game.Rules) *Tracker {
+	t := &Tracker{rules: rules}
+	for _, c := range rules.Deck {
+		t.copies[c]++
+	}
+	return t
+}
+
+// Init begins tracking a new hand, given the number of players, the tracking
+// player's position in the deal and their cards. Its signature matches that of
+// the Player method, and stake and kitty are ignored.
+func (t *Tracker) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	t.n, t.pos = n, pos
+	t.mine, t.played, t.shown = [52]int{}, [52]int{}, [52]int{}
+	for _, c := range hand {
+		t.mine[c]++
+	}
+	// The deck is dealt one card at a time to each player and then the
+	// widow.
+	t.count = make([]int, n+1)
+	for i := range t.rules.Deck {
+		t.count[i%(n+1)]++
+	}
+	t.count[pos] = len(hand)
+	all := uint16(1)<<uint(n+1) - 1
+	for c := range t.allow {
+		t.allow[c] = all &^ (1 << uint(pos))
+	}
+	t.started = false
+	t.prob = nil
+}
+
+// Note informs the Tracker that the player at the indicated position played a
+// card. Its signature matches that of the Player method.
+func (t *Tracker) Note(pos int, c card.Card) {
+	switch next, ok := t.next(t.last); {
+	case !t.started:
+		t.firstLead(pos, c)
+	case c == t.last:
+		// Another copy of the same card.
+	case ok && c == next:
+		t.exhaust(t.last)
+	default:
+		t.exhaust(t.last)
+		if ok {
+			t.exhaust(next)
+		}
+		t.lead(pos, c)
+	}
+	t.started, t.last = true, c
+	if pos == t.pos {
+		t.mine[c]--
+	}
+	t.played[c]++
+	t.count[pos]--
+	t.prob = nil
+}
+
+// Pass informs the Tracker that the player at the indicated position could not
+// lead from any of the given suits. Its signature matches that of the Listener
+// method.
+func (t *Tracker) Pass(pos int, suits []card.Suit) {
+	for _, s := range suits {
+		for c := s.Rank(card.Two); c <= s.Rank(card.Ace); c++ {
+			t.allow[c] &^= 1 << uint(pos)
+		}
+	}
+	t.prob = nil
+}
+
+// Show informs the Tracker that a copy of a card is in the widow, as the card
+// turned up to determine the trump suit is.
+func (t *Tracker) Show(c card.Card) {
+	t.shown[c]++
+	t.prob = nil
+}
+
+// next returns the card that follows c in a run, and a boolean value reporting
+// whether the run may continue past c.
+func (t *Tracker) next(c card.Card) (card.Card, bool) {
+	if c.Rank() != card.Ace {
+		return c + 1, true
+	}
+	return c.Suit().Rank(card.Two), t.rules.Wrap
+}
+
+// firstLead records the deductions from the first card of a hand, which is led
+// by the player at the indicated position.
+func (t *Tracker) firstLead(pos int, c card.Card) {
+	if t.rules.Eldest {
+		t.lead(pos, c)
+		return
+	}
+	// No player held any card from the lowest card of the Rules' Start suit
+	// up to c.
+	for d := t.rules.Start.Rank(card.Two); d != c; d = (d + 1) % 52 {
+		t.exhaust(d)
+	}
+}
+
+// lead records that the player at the indicated position led a card, and so
+// holds no lower card in its suit.
+func (t *Tracker) lead(pos int, c card.Card) {
+	for d := c.Suit().Rank(card.Two); d < c; d++ {
+		t.allow[d] &^= 1 << uint(pos)
+	}
+}
+
+// exhaust records that no player holds a copy of a card.
+func (t *Tracker) exhaust(c card.Card) {
+	t.allow[c] = 1 << uint(t.n)
+}
+
+// Widow returns the seat of the widow.
+func (t *Tracker) Widow() int { return t.n }
+
+// Count returns the number of cards held by a seat.
+func (t *Tracker) Count(seat int) int { return t.count[seat] }
+
+// Unseen returns the number of copies of a card whose locations are unknown to
+// the tracking player: those that have not been played and are neither in
+// their hand nor known to be in the widow.
+func (t *Tracker) Unseen(c card.Card) int {
+	return t.copies[c] - t.mine[c] - t.played[c] - t.shown[c]
+}
+
+// May reports whether a seat may hold a copy of a card.
+func (t *Tracker) May(seat int, c card.Card) bool {
+	switch {
+	case seat == t.pos:
+		return t.mine[c] > 0
+	case seat == t.n && t.shown[c] > 0:
+		return true
+	}
+	return t.Unseen(c) > 0 && t.allow[c]&(1<<uint(seat)) != 0
+}
+
+// Candidates returns in order the cards that a seat may hold.
+func (t *Tracker) Candidates(seat int) []card.Card {
+	var cs []card.Card
+	for c := card.Card(0); c < 52; c++ {
+		if t.May(seat, c) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// Prob returns the probability that a seat holds a particular copy of a card
+// whose location is unknown, assuming that every deal consistent with what the
+// Tracker knows is equally likely. With a single deck, it is the probability
+// that the seat holds the card. Prob returns 0 for a card with no unseen
+// copies, or if no deal is consistent with what the Tracker knows.
+func (t *Tracker) Prob(seat int, c card.Card) float64 {
+	if t.prob == nil {
+		t.prob = t.compute()
+	}
+	return t.prob[seat][c]
+}
+
+// A class is a set of unseen cards that may be held by the same seats.
+type class struct {
+	allow uint16
+	m     int
+	cards []card.Card
+}
+
+// A group is a set of seats that may hold the same classes of cards.
+type group struct {
+	allow uint64
+	c     int
+	seats []int
+}
+
+// compute computes the probability of each unseen card's location.
+//
+// The number of deals consistent with what the Tracker knows is proportional
+// to the sum, over every way to choose how many cards of each class each group
+// of seats holds, of the product of 1/y! for each such number y. Seats whose
+// constraints are the same are interchangeable, as are cards, so the sum is
+// computed over the much smaller table of groups and classes.
+func (t *Tracker) compute() [][52]float64 {
+	prob := make([][52]float64, t.n+1)
+	var classes []class
+	for c := card.Card(0); c < 52; c++ {
+		u := t.Unseen(c)
+		if u <= 0 {
+			continue
+		}
+		i := 0
+		for i < len(classes) && classes[i].allow != t.allow[c] {
+			i++
+		}
+		if i == len(classes) {
+			classes = append(classes, class{allow: t.allow[c]})
+		}
+		classes[i].m += u
+		classes[i].cards = append(classes[i].cards, c)
+	}
+	caps := make([]int, t.n+1)
+	copy(caps, t.count)
+	caps[t.pos] = 0
+	for c := range t.shown {
+		caps[t.n] -= t.shown[c]
+	}
+	var groups []group
+	for seat, c := range caps {
+		if c <= 0 {
+			continue
+		}
+		var allow uint64
+		for k := range classes {
+			if classes[k].allow&(1<<uint(seat)) != 0 {
+				allow |= 1 << uint(k)
+			}
+		}
+		i := 0
+		for i < len(groups) && groups[i].allow != allow {
+			i++
+		}
+		if i == len(groups) {
+			groups = append(groups, group{allow: allow})
+		}
+		groups[i].c += c
+		groups[i].seats = append(groups[i].seats, seat)
+	}
+	rows := make([]int, len(groups))
+	for g := range groups {
+		rows[g] = groups[g].c
+	}
+	cols := make([]int, len(classes))
+	for k := range classes {
+		cols[k] = classes[k].m
+	}
+	allow := func(g, k int) bool { return groups[g].allow&(1<<uint(k)) != 0 }
+	total := sum(rows, cols, allow)
+	if total == 0 {
+		return prob
+	}
+	for g := range groups {
+		for k := range classes {
+			if !allow(g, k) {
+				continue
+			}
+			// The expected number of cards of the class in the group.
+			rows[g]--
+			cols[k]--
+			e := sum(rows, cols, allow) / total
+			rows[g]++
+			cols[k]++
+			for _, seat := range groups[g].seats {
+				p := e / float64(classes[k].m) * float64(caps[seat]) / float64(groups[g].c)
+				for _, c := range classes[k].cards {
+					prob[seat][c] = p
+				}
+			}
+		}
+	}
+	return prob
+}
+
+// sum returns the sum, over every table of non-negative integers y with the
+// given row and column sums that is zero wherever allow is false, of the
+// product of 1/y! for each entry y.
+func sum(rows, cols []int, allow func(r, c int) bool) float64 {
+	for _, n := range append(rows[:len(rows):len(rows)], cols...) {
+		if n < 0 {
+			return 0
+		}
+	}
+	// The table is filled in row by row, and the state records the amount
+	// remaining in each column, which is packed into a key, so there must be
+	// few enough columns.
+	if len(cols) > 64/colBits || len(cols) > len(rows) {
+		return sum(cols, rows, func(r, c int) bool { return allow(c, r) })
+	}
+	last := make([]int, len(cols))
+	for c := range cols {
+		last[c] = -1
+		for r := range rows {
+			if allow(r, c) {
+				last[c] = r
+			}
+		}
+		if last[c] == -1 && cols[c] > 0 {
+			return 0
+		}
+	}
+	var start uint64
+	for c, n := range cols {
+		start |= uint64(n) << uint(c*colBits)
+	}
+	type state struct {
+		cols uint64
+		rem  int
+	}
+	frontier := map[state]float64{{start, 0}: 1}
+	for r, n := range rows {
+		var cells []int
+		for c := range cols {
+			if allow(r, c) {
+				cells = append(cells, c)
+			}
+		}
+		if len(cells) == 0 && n > 0 {
+			return 0
+		}
+		next := make(map[state]float64, len(frontier))
+		for st, w := range frontier {
+			next[state{st.cols, n}] += w
+		}
+		frontier = next
+		for i, c := range cells {
+			shift := uint(c * colBits)
+			next := make(map[state]float64, len(frontier))
+			for st, w := range frontier {
+				col := int(st.cols >> shift & (1<<colBits - 1))
+				lo, hi := 0, st.rem
+				if col < hi {
+					hi = col
+				}
+				// The last cell of a row must complete it, and the last
+				// cell of a column must complete it.
+				if i == len(cells)-1 {
+					lo = st.rem
+				}
+				if last[c] == r && col > lo {
+					lo = col
+				}
+				for x := lo; x <= hi; x++ {
+					next[state{st.cols - uint64(x)<<shift, st.rem - x}] += w / factorial(x)
+				}
+			}
+			frontier = next
+		}
+	}
+	return frontier[state{0, 0}]
+}
+
+// colBits is the number of bits in which sum packs each column's sum.
+const colBits = 7
+
+// factorials records n! for each n up to the size of a double deck.
+var factorials = func() (f [105]float64) {
+	f[0] = 1
+	for n := 1; n < len(f); n++ {
+		f[n] = f[n-1] * float64(n)
+	}
+	return f
+}()
+
+// factorial returns n!.
+func factorial(n int) float64 { return factorials[n] }
@@ -0,0 +1,240 @@
+package track
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+func TestDeductions(t *testing.T) {
+	// Position 0 of 3 players holds the twos and threes of clubs and
+	// diamonds.
+	hand := []card.Card{0, 1, 13, 14}
+	for name, test := range map[string]struct {
+		notes func(*Tracker)
+		seat  int
+		c     card.Card
+		may   bool
+	}{
+		"initial": {func(*Tracker) {}, 1, 5, true},
+		"played":  {func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2) }, 1, 2, false},
+		"passed": {
+			func(tr *Tracker) {
+				tr.Note(0, 0)
+				tr.Note(0, 1)
+				tr.Note(1, 2)
+				tr.Pass(2, []card.Suit{card.Diamonds, card.Hearts})
+			},
+			2, card.Hearts.Rank(card.Ace), false,
+		},
+		"continued": {func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2); tr.Note(2, 3) }, 1, 2, false},
+		"stopped": {
+			func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2); tr.Note(1, 40) },
+			1, 3, false,
+		},
+		"stopped widow": {
+			func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2); tr.Note(1, 40) },
+			3, 3, true,
+		},
+		"lead": {
+			func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2); tr.Note(2, 44) },
+			2, 42, false,
+		},
+		"lead higher": {
+			func(tr *Tracker) { tr.Note(0, 0); tr.Note(0, 1); tr.Note(1, 2); tr.Note(2, 44) },
+			2, 46, true,
+		},
+		"mine":     {func(*Tracker) {}, 0, 13, true},
+		"not mine": {func(*Tracker) {}, 0, 15, false},
+	} {
+		tr := New(game.Michigan())
+		tr.Init(3, 0, hand, nil, 0)
+		test.notes(tr)
+		if may := tr.May(test.seat, test.c); may != test.may {
+			t.Errorf("May(%q, %v, %v) = %v, expected %v", name, test.seat, test.c, may, test.may)
+		}
+	}
+}
+
+func TestFirstLead(t *testing.T) {
+	tr := New(game.Michigan())
+	tr.Init(2, 0, []card.Card{20, 30}, nil, 0)
+	tr.Note(1, 3)
+	if got, want := tr.Candidates(tr.Widow())[:3], []card.Card{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Candidates(widow) begins with %v, expected %v", got, want)
+	}
+	if tr.May(1, 2) {
+		t.Errorf("May(1, 2) = true after the first lead of 3")
+	}
+	if n := tr.Count(1); n != 16 {
+		t.Errorf("Count(1) = %v, expected 16", n)
+	}
+}
+
+// brute returns the probability of each unseen card's location by
+// enumerating every distribution of the unseen cards that is consistent with
+// what a Tracker knows.
+func brute(tr *Tracker) [][52]float64 {
+	var cards []card.Card
+	for c := card.Card(0); c < 52; c++ {
+		for k := 0; k < tr.Unseen(c); k++ {
+			cards = append(cards, c)
+		}
+	}
+	caps := append([]int(nil), tr.count...)
+	caps[tr.pos] = 0
+	for _, n := range tr.shown {
+		caps[tr.n] -= n
+	}
+	counts := make([][52]float64, tr.n+1)
+	var total float64
+	seat := make([]int, len(cards))
+	var assign func(i int)
+	assign = func(i int) {
+		if i == len(cards) {
+			total++
+			for j, c := range cards {
+				counts[seat[j]][c]++
+			}
+			return
+		}
+		for s := range caps {
+			if caps[s] > 0 && tr.allow[cards[i]]&(1<<uint(s)) != 0 {
+				caps[s]--
+				seat[i] = s
+				assign(i + 1)
+				caps[s]++
+			}
+		}
+	}
+	assign(0)
+	for s := range counts {
+		for c := range counts[s] {
+			if u := tr.Unseen(card.Card(c)); u > 0 {
+				counts[s][c] /= total * float64(u)
+			}
+		}
+	}
+	return counts
+}
+
+func TestProb(t *testing.T) {
+	rules := game.Michigan()
+	rules.Deck = card.Stripped(card.Jack)
+	for name, f := range map[string]func(*Tracker){
+		"deal": func(*Tracker) {},
+		"pass": func(tr *Tracker) { tr.Pass(1, []card.Suit{card.Spades}) },
+		"lead": func(tr *Tracker) { tr.Note(1, card.Clubs.Rank(card.Ace)); tr.Note(2, card.Hearts.Rank(card.King)) },
+		"show": func(tr *Tracker) { tr.Show(card.Diamonds.Rank(card.Ace)) },
+	} {
+		tr := New(rules)
+		tr.Init(3, 0, []card.Card{9, 10, 22, 23}, nil, 0)
+		f(tr)
+		want := brute(tr)
+		for s := range want {
+			for c := range want[s] {
+				if p := tr.Prob(s, card.Card(c)); math.Abs(p-want[s][c]) > 1e-9 {
+					t.Errorf("Prob(%q, %v, %v) = %v, expected %v", name, s, card.Card(c), p, want[s][c])
+				}
+			}
+		}
+	}
+}
+
+// truth is a Listener that checks whenever a card is played that its Tracker
+// allows the true location of every card.
+type truth struct {
+	t   *testing.T
+	tr  *Tracker
+	loc *[][52]int
+	pos int
+}
+
+func (p *truth) Init(n, pos int, hand []card.Card, stake []int, kitty int) {
+	p.pos = pos
+	p.tr.Init(n, pos, hand, stake, kitty)
+}
+
+func (p *truth) Note(pos int, c card.Card) {
+	p.tr.Note(pos, c)
+	if p.pos == 0 {
+		// Position 0 is informed first, and updates the true locations.
+		(*p.loc)[pos][c]--
+	}
+	p.check()
+}
+
+func (p *truth) PlayMajor(game.TableState, card.Color) bool { return p.pos%2 == 0 }
+
+func (p *truth) Pass(pos int, suits []card.Suit) { p.tr.Pass(pos, suits) }
+
+func (p *truth) PayKitty(int, int) {}
+
+func (p *truth) Collect(int, string, int) {}
+
+func (p *truth) HandEnd(game.Result) {}
+
+func (p *truth) check() {
+	for s, held := range *p.loc {
+		for c, k := range held {
+			if k > 0 && !p.tr.May(s, card.Card(c)) {
+				p.t.Fatalf("position %v: seat %v holds %v, which the Tracker does not allow", p.pos, s, card.Card(c))
+			}
+			if k > 0 && p.pos == 0 && s != 0 && p.tr.Prob(s, card.Card(c)) == 0 {
+				p.t.Fatalf("position %v: seat %v holds %v, whose probability is 0", p.pos, s, card.Card(c))
+			}
+		}
+	}
+}
+
+func TestTruth(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := game.MinPlayers + rng.Intn(game.MaxPlayers-game.MinPlayers+1)
+		rules := game.Michigan()
+		rules.Deck = game.DeckFor(n)
+		rules.Wrap = i%2 == 1
+		rules.Deck.Shuffle(rng)
+		d := game.Deal{Hands: make([][]card.Card, n)}
+		loc := make([][52]int, n+1)
+		for j, c := range rules.Deck {
+			s := j % (n + 1)
+			loc[s][c]++
+			if s == n {
+				d.Widow = append(d.Widow, c)
+			} else {
+				d.Hands[s] = append(d.Hands[s], c)
+			}
+		}
+		players := make([]game.Player, n)
+		for j := range players {
+			players[j] = &truth{t: t, tr: New(rules), loc: &loc}
+		}
+		// The dealer is the last player, so that players are seated in
+		// order.
+		game.New(players, rules).PlayDeal(d, n-1)
+	}
+}
+
+func BenchmarkProb(b *testing.B) {
+	rules := game.Michigan()
+	rules.Deck = game.DeckFor(9)
+	rules.Deck.Shuffle(rand.New(rand.NewSource(1)))
+	var hand []card.Card
+	for j := 0; j < len(rules.Deck); j += 10 {
+		hand = append(hand, rules.Deck[j])
+	}
+	tr := New(rules)
+	tr.Init(9, 0, hand, nil, 0)
+	tr.Pass(3, []card.Suit{card.Clubs, card.Spades})
+	tr.Pass(5, []card.Suit{card.Diamonds})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tr.prob = nil
+		tr.Prob(1, 0)
+	}
+}
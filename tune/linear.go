@@ -0,0 +1,113 @@
+package tune
+
+import (
+	"strconv"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+	"github.com/dkmccandless/tripoli/playerutil"
+)
+
+// Features names the features of a suit that a Linear Player weighs, in the
+// order of its Weights:
+//
+//	counter  1 if a card held in the suit collects a Pot, and otherwise 0
+//	stake    the number of chips in the Pots collected by the cards held in
+//	         the suit
+//	run      the number of consecutive ranks held beginning with the lowest
+//	len      the number of cards held in the suit
+//	low      the rank of the lowest card held in the suit, from 2 to 14 for an
+//	         ace, or 0 if none is held, as in package script
+var Features = []string{"counter", "stake", "run", "len", "low"}
+
+// DefaultWeights are the Weights of a Linear Player that has not been tuned.
+var DefaultWeights = []float64{1, 0.5, 1, 0, 0}
+
+// A Linear is a Player that values each suit by a weighted sum of its Features,
+// and leads from the major suit if it values it more than the minor suit.
+type Linear struct {
+	rules   game.Rules
+	weights []float64
+
+	playerutil.Hand
+}
+
+// NewLinear returns a Linear Player with the given Weights, one for each of
+// the Features, in games played according to the given Rules.
+func NewLinear(weights []float64, rules game.Rules) *Linear {
+	if len(weights) != len(Features) {
+		panic("tune: wrong number of weights")
+	}
+	return &Linear{rules: rules, weights: append([]float64(nil), weights...)}
+}
+
+// PlayMajor implements game.Player.
+func (l *Linear) PlayMajor(t game.TableState, color card.Color) bool {
+	return l.value(t, color.Major()) > l.value(t, color.Minor())
+}
+
+// value returns the weighted sum of the features of a suit.
+func (l *Linear) value(t game.TableState, s card.Suit) float64 {
+	var f [5]float64
+	var held [13]bool
+	for _, c := range l.Cards {
+		if c.Suit() != s {
+			continue
+		}
+		held[c.Rank()] = true
+		f[3]++
+		for i, p := range l.rules.Layout {
+			for _, pc := range p.Cards {
+				if p.Trump {
+					pc = t.Trump.Rank(pc.Rank())
+				}
+				if pc == c {
+					f[0] = 1
+					if i < len(t.Stake) {
+						f[1] += float64(t.Stake[i])
+					}
+				}
+			}
+		}
+	}
+	low := 0
+	for low < 13 && !held[low] {
+		low++
+	}
+	if low < 13 {
+		f[4] = float64(low + 2)
+	}
+	for r := low; r < 13 && held[r]; r++ {
+		f[2]++
+	}
+	var v float64
+	for i, w := range l.weights {
+		v += w * f[i]
+	}
+	return v
+}
+
+func init() {
+	opts := make([]bots.Option, len(Features))
+	for i, name := range Features {
+		opts[i] = bots.Option{
+			Name:    name,
+			Kind:    bots.Float,
+			Default: strconv.FormatFloat(DefaultWeights[i], 'g', -1, 64),
+			Help:    "the weight of the " + name + " feature",
+		}
+	}
+	bots.Register(bots.Bot{
+		Name:    "linear",
+		Help:    "values each suit by a weighted sum of its features, as tuned by package tune",
+		Options: opts,
+		New: func(o bots.Options) (game.Player, error) {
+			weights := make([]float64, len(Features))
+			for i, name := range Features {
+				weights[i] = o.Float(name)
+			}
+			return NewLinear(weights, o.Rules), nil
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package tune
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/dkmccandless/tripoli/abtest"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// A Match compares parameters by playing duplicate deals, as package abtest
+// does, between a Player with one set of parameters in one seat and Players
+// with the other in the rest.
+type Match struct {
+	Rules   game.Rules
+	Players int
+
+	// New returns a new Player with the given parameters, seeded with the
+	// given value. It must be safe to call from more than one goroutine.
+	New func(params []float64, seed int64) game.Player
+
+	// Pairs is the number of pairs of hands of each comparison. It must be
+	// positive.
+	Pairs int
+
+	// Workers is the number of goroutines that play the pairs, or if it is
+	// not positive, the number of CPUs that may run Go code at once.
+	Workers int
+}
+
+// chunk is the number of pairs that a goroutine of Compare plays at a time.
+// The deals of each chunk depend only on the seed of the comparison, so that
+// the result does not depend on the number of goroutines.
+const chunk = 50
+
+// Compare implements Compare by returning the mean number of chips per pair of
+// hands by which a Player with the parameters plus outplays one with the
+// parameters minus. It returns an error if Pairs is not positive.
+func (m Match) Compare(plus, minus []float64, seed int64) (float64, error) {
+	if m.Pairs <= 0 {
+		return 0, fmt.Errorf("tune: %v pairs in a Match, expected a positive number", m.Pairs)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	seeds := make([]int64, (m.Pairs+chunk-1)/chunk)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+	workers := m.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	sums := make([]float64, len(seeds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				n := m.Pairs - i*chunk
+				if n > chunk {
+					n = chunk
+				}
+				r := abtest.Run(abtest.Config{
+					Rules:     m.Rules,
+					Players:   m.Players,
+					Candidate: func(seed int64) game.Player { return m.New(plus, seed) },
+					Baseline:  func(seed int64) game.Player { return m.New(minus, seed) },
+					MaxPairs:  n,
+					Seed:      seeds[i],
+				})
+				sums[i] = r.Mean * float64(r.Pairs)
+			}
+		}()
+	}
+	for i := range seeds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	var total float64
+	for _, s := range sums {
+		total += s
+	}
+	return total / float64(m.Pairs), nil
+}
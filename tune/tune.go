@@ -0,0 +1,203 @@
+// Package tune optimizes the numeric parameters of a Player by simultaneous
+// perturbation stochastic approximation (SPSA).
+//
+// Each iteration perturbs every parameter at once, in a random direction, by
+// plus and minus a small amount, and plays a match between the two perturbed
+// Players to estimate which is better. The parameters then move toward the
+// better one, by steps that shrink as the iterations proceed.
+//
+// An SPSA is reproducible: the perturbations and the deals of each iteration
+// are drawn from random number generators seeded with the SPSA's seed and the
+// number of the iteration, so that the same seed gives the same results, and
+// an SPSA resumed from a checkpoint continues exactly as if it had not
+// stopped.
+package tune
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// A Compare function estimates how much better the parameters plus perform
+// than the parameters minus, drawing any randomness from a random number
+// generator seeded with the given value. It returns an error if it cannot
+// compare them.
+type Compare func(plus, minus []float64, seed int64) (float64, error)
+
+// An SPSA optimizes a vector of parameters.
+type SPSA struct {
+	compare Compare
+
+	// The step size of iteration k, counting from 0, is
+	// A/(k+1+Stability)^Alpha, and the size of its perturbation is
+	// C/(k+1)^Gamma.
+	A, Alpha, Stability float64
+	C, Gamma            float64
+
+	seed   int64
+	params []float64
+	k      int
+}
+
+// NewSPSA returns an SPSA that optimizes the given parameters with compare,
+// seeded with the given value, with customary gains for parameters of unit
+// scale.
+func NewSPSA(params []float64, compare Compare, seed int64) *SPSA {
+	return &SPSA{
+		compare:   compare,
+		A:         0.5,
+		Alpha:     0.602,
+		Stability: 10,
+		C:         0.5,
+		Gamma:     0.101,
+		seed:      seed,
+		params:    append([]float64(nil), params...),
+	}
+}
+
+// Params returns the current parameters.
+func (s *SPSA) Params() []float64 { return append([]float64(nil), s.params...) }
+
+// Iteration returns the number of iterations run.
+func (s *SPSA) Iteration() int { return s.k }
+
+// Step runs an iteration. If the comparison fails, Step returns its error and
+// leaves the SPSA unchanged.
+func (s *SPSA) Step() error {
+	rng := rand.New(rand.NewSource(mix(s.seed, s.k)))
+	k := float64(s.k)
+	a := s.A / math.Pow(k+1+s.Stability, s.Alpha)
+	c := s.C / math.Pow(k+1, s.Gamma)
+	delta := make([]float64, len(s.params))
+	plus := make([]float64, len(s.params))
+	minus := make([]float64, len(s.params))
+	for i, p := range s.params {
+		delta[i] = float64(2*rng.Intn(2) - 1)
+		plus[i], minus[i] = p+c*delta[i], p-c*delta[i]
+	}
+	y, err := s.compare(plus, minus, rng.Int63())
+	if err != nil {
+		return err
+	}
+	for i := range s.params {
+		s.params[i] += a * y / (2 * c * delta[i])
+	}
+	s.k++
+	return nil
+}
+
+// mix returns the seed of iteration k of an SPSA seeded with seed, by a step
+// of the SplitMix64 generator, so that SPSAs whose seeds are close together do
+// not share the perturbations and deals of their iterations.
+func mix(seed int64, k int) int64 {
+	z := uint64(seed) + uint64(k+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
+
+// Run runs the given number of iterations. If checkpoint is not empty, it
+// saves a checkpoint to the named file after each iteration. If progress is
+// not nil, it is called after each iteration.
+func (s *SPSA) Run(iterations int, checkpoint string, progress func(s *SPSA)) error {
+	for i := 0; i < iterations; i++ {
+		if err := s.Step(); err != nil {
+			return err
+		}
+		if checkpoint != "" {
+			if err := s.Save(checkpoint); err != nil {
+				return err
+			}
+		}
+		if progress != nil {
+			progress(s)
+		}
+	}
+	return nil
+}
+
+// CheckpointVersion is the version of the checkpoint format written by Save.
+const CheckpointVersion = 2
+
+// A checkpoint records the state of an SPSA.
+type checkpoint struct {
+	Version   int
+	Seed      int64
+	Iteration int
+	Params    []float64
+
+	A, Alpha, Stability float64
+	C, Gamma            float64
+}
+
+// Save writes a checkpoint of the SPSA's state to the named file, replacing it
+// only once the checkpoint is complete.
+func (s *SPSA) Save(name string) error {
+	data, err := json.MarshalIndent(checkpoint{
+		Version:   CheckpointVersion,
+		Seed:      s.seed,
+		Iteration: s.k,
+		Params:    s.params,
+		A:         s.A,
+		Alpha:     s.Alpha,
+		Stability: s.Stability,
+		C:         s.C,
+		Gamma:     s.Gamma,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Resume returns an SPSA that optimizes n parameters with compare from the
+// state recorded in a checkpoint file written by Save. Resume returns an error
+// if the checkpoint does not hold n parameters.
+func Resume(name string, n int, compare Compare) (*SPSA, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("tune: %v: %v", name, err)
+	}
+	switch {
+	case c.Version != CheckpointVersion:
+		return nil, fmt.Errorf("tune: %v: checkpoint version %v, expected %v", name, c.Version, CheckpointVersion)
+	case c.Iteration < 0 || len(c.Params) == 0:
+		return nil, errors.New("tune: " + name + ": malformed checkpoint")
+	case len(c.Params) != n:
+		return nil, fmt.Errorf("tune: %v: checkpoint has %v parameters, expected %v", name, len(c.Params), n)
+	}
+	return &SPSA{
+		compare:   compare,
+		A:         c.A,
+		Alpha:     c.Alpha,
+		Stability: c.Stability,
+		C:         c.C,
+		Gamma:     c.Gamma,
+		seed:      c.Seed,
+		params:    c.Params,
+		k:         c.Iteration,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package tune
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/tripoli/bots"
+	"github.com/dkmccandless/tripoli/card"
+	"github.com/dkmccandless/tripoli/game"
+)
+
+// target is the optimum of quadratic.
+var target = []float64{1, -2, 0.5}
+
+// quadratic is a noisy Compare whose parameters are better the nearer they
+// are to target.
+func quadratic(plus, minus []float64, seed int64) (float64, error) {
+	loss := func(x []float64) float64 {
+		var sum float64
+		for i, t := range target {
+			sum += (x[i] - t) * (x[i] - t)
+		}
+		return sum
+	}
+	noise := rand.New(rand.NewSource(seed)).NormFloat64() * 0.1
+	return loss(minus) - loss(plus) + noise, nil
+}
+
+func TestConverge(t *testing.T) {
+	s := NewSPSA([]float64{0, 0, 0}, quadratic, 1)
+	if err := s.Run(2000, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range s.Params() {
+		if math.Abs(p-target[i]) > 0.1 {
+			t.Errorf("Params() = %v, expected near %v", s.Params(), target)
+			break
+		}
+	}
+	if s.Iteration() != 2000 {
+		t.Errorf("Iteration() = %v, expected 2000", s.Iteration())
+	}
+}
+
+func TestResume(t *testing.T) {
+	straight := NewSPSA([]float64{0, 0, 0}, quadratic, 7)
+	var calls int
+	straight.Run(4, "", func(*SPSA) { calls++ })
+	if calls != 4 {
+		t.Errorf("progress called %v times, expected 4", calls)
+	}
+
+	name := filepath.Join(t.TempDir(), "checkpoint.json")
+	first := NewSPSA([]float64{0, 0, 0}, quadratic, 7)
+	if err := first.Run(2, name, nil); err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := Resume(name, 3, quadratic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Iteration() != 2 || !reflect.DeepEqual(resumed.Params(), first.Params()) {
+		t.Errorf("Resume: iteration %v with Params() %v, expected 2 with %v", resumed.Iteration(), resumed.Params(), first.Params())
+	}
+	resumed.Run(2, "", nil)
+	if !reflect.DeepEqual(resumed.Params(), straight.Params()) {
+		t.Errorf("resumed Params() = %v, expected %v", resumed.Params(), straight.Params())
+	}
+
+	if _, err := Resume(filepath.Join(t.TempDir(), "missing.json"), 3, quadratic); err == nil {
+		t.Error("Resume of a missing file returned no error")
+	}
+	if _, err := Resume(name, 5, quadratic); err == nil {
+		t.Error("Resume of a checkpoint with 3 parameters for 5 returned no error")
+	}
+
+	failing := NewSPSA([]float64{0, 0, 0}, func([]float64, []float64, int64) (float64, error) {
+		return 0, errors.New("failed")
+	}, 7)
+	if err := failing.Run(2, "", nil); err == nil || failing.Iteration() != 0 {
+		t.Errorf("Run with a failing Compare: error %v after %v iterations, expected an error after 0", err, failing.Iteration())
+	}
+}
+
+func TestMix(t *testing.T) {
+	// SPSAs with adjacent seeds must not share the seeds of their iterations.
+	seen := make(map[int64]bool)
+	for seed := int64(0); seed < 10; seed++ {
+		for k := 0; k < 10; k++ {
+			m := mix(seed, k)
+			if seen[m] {
+				t.Fatalf("mix(%v, %v) = %v repeats an earlier seed", seed, k, m)
+			}
+			seen[m] = true
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	m := Match{
+		Rules:   game.Michigan(),
+		Players: 4,
+		New: func(params []float64, seed int64) game.Player {
+			return NewLinear(params, game.Michigan())
+		},
+		Pairs: 120,
+	}
+	plus, minus := DefaultWeights, []float64{0, 0, 0, 0, 0}
+	var want float64
+	for _, workers := range []int{1, 3, 8} {
+		m.Workers = workers
+		got, err := m.Compare(plus, minus, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if workers == 1 {
+			want = got
+		} else if got != want {
+			t.Errorf("Compare with %v workers = %v, expected %v", workers, got, want)
+		}
+	}
+	if same, err := m.Compare(minus, minus, 5); err != nil || same != 0 {
+		t.Errorf("Compare of equal parameters = %v, %v, expected 0", same, err)
+	}
+	m.Pairs = 0
+	if _, err := m.Compare(plus, minus, 5); err == nil {
+		t.Error("Compare with no pairs returned no error")
+	}
+}
+
+func TestLinear(t *testing.T) {
+	hand := []card.Card{
+		card.Diamonds.Rank(card.Two), card.Diamonds.Rank(card.Three), card.Diamonds.Rank(card.Four),
+		card.Hearts.Rank(card.Ace), card.Spades.Rank(card.Five),
+	}
+	ts := game.TableState{Stake: make([]int, len(game.Michigan().Layout))}
+	for name, test := range map[string]struct {
+		weights []float64
+		color   card.Color
+		want    bool
+	}{
+		"run":     {[]float64{0, 0, 1, 0, 0}, card.Red, false},
+		"len":     {[]float64{0, 0, 0, 1, 0}, card.Red, false},
+		"low":     {[]float64{0, 0, 0, 0, 1}, card.Red, true},
+		"none":    {[]float64{0, 0, 0, 0, 1}, card.Black, true},
+		"counter": {[]float64{1, 0, 0, 0, 0}, card.Red, true},
+	} {
+		l := NewLinear(test.weights, game.Michigan())
+		l.Init(4, 0, hand, ts.Stake, 0)
+		if got := l.PlayMajor(ts, test.color); got != test.want {
+			t.Errorf("%v: PlayMajor = %v, expected %v", name, got, test.want)
+		}
+	}
+
+	specs, err := bots.Parse("linear:len=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := specs.New(game.Michigan(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]float64(nil), DefaultWeights...)
+	want[3] = 2
+	if got := p[0].(*Linear).weights; !reflect.DeepEqual(got, want) {
+		t.Errorf("bot weights %v, expected %v", got, want)
+	}
+}